@@ -8,18 +8,18 @@
 
 package main
 
-import (
-	"net/http"
-
-	"golang.org/x/tools/blog"
-)
+import "net/http"
 
 func init() {
-	config.ContentPath = "content/"
 	config.TemplatePath = "template/"
-	s, err := blog.NewServer(config)
+	// Without content_en/, the Chinese blog alone is served at /.
+	s, err := newLangServer(config, "content_en/", "content/")
+	if err != nil {
+		panic(err)
+	}
+	rd, err := newRedirector(redirectFile, s, s)
 	if err != nil {
 		panic(err)
 	}
-	http.Handle("/", s)
+	http.Handle("/", rd)
 }
@@ -0,0 +1,180 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !appengine
+
+// This file implements the export of the blog as static files.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/godoc/static"
+)
+
+// export writes the blog served by h as static files below dir, which must
+// not exist or be empty: the home pages, the indexes, the feeds and the
+// articles in both languages, the files of the content directories, the
+// /lib/godoc/ and /static/ files, and a page for every redirect of rd,
+// which sends the browser on with a meta refresh. Without the English
+// content, the Chinese blog is written at the root.
+//
+// Every file is at the path of its URL, the paths ending in a slash being
+// index.html files, except the articles, such as "/go-maps-in-action",
+// which get an .html extension, as "5years.html" goes with the directory of
+// its images, "5years". The web server must serve them at their URL without
+// the extension, as GitHub Pages does and as nginx does with
+// "try_files $uri $uri.html", and serve the "/index" files as text/html.
+// The output depends only on the content, the templates and the static
+// files.
+func export(dir string, s *langServer, rd *redirector, h http.Handler) error {
+	if names, err := ioutil.ReadDir(dir); err == nil && len(names) > 0 {
+		return fmt.Errorf("export: %s is not empty", dir)
+	}
+
+	base := zhBasePath // of the Chinese blog
+	if s.en == nil {
+		base = ""
+	}
+	paths := []string{"/", "/index", "/feed.atom", "/.json"}
+	if base != "" {
+		for _, p := range paths {
+			paths = append(paths, base+p)
+		}
+	}
+	for _, slug := range s.slugs() {
+		if s.english("/" + slug) {
+			paths = append(paths, "/"+slug)
+		}
+		paths = append(paths, base+"/"+slug)
+	}
+	var enFiles []string
+	var err error
+	if s.en != nil {
+		if enFiles, err = files(s.enContent, ".article"); err != nil {
+			return err
+		}
+	}
+	zhFiles, err := files(s.zhContent, ".article")
+	if err != nil {
+		return err
+	}
+	for _, f := range enFiles {
+		paths = append(paths, "/"+f)
+	}
+	// The Chinese articles that fall back to English show the files of
+	// the English ones.
+	for _, f := range append(enFiles, zhFiles...) {
+		paths = append(paths, base+"/"+f)
+	}
+	for name := range static.Files {
+		paths = append(paths, "/lib/godoc/"+name)
+	}
+	staticFiles, err := files(*staticPath, "")
+	if err != nil {
+		return err
+	}
+	for _, f := range staticFiles {
+		paths = append(paths, "/static/"+f)
+	}
+	sort.Strings(paths)
+
+	pages := make(map[string]bool)
+	for _, slug := range s.slugs() {
+		pages["/"+slug] = true
+		pages[base+"/"+slug] = true
+	}
+	last := ""
+	for _, p := range paths {
+		if p == last {
+			continue
+		}
+		last = p
+		r, err := http.NewRequest("GET", p, nil)
+		if err != nil {
+			return err
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			return fmt.Errorf("export: GET %s: %d %s", p, w.Code, http.StatusText(w.Code))
+		}
+		name := p
+		if pages[p] {
+			name += ".html"
+		}
+		if err := write(dir, name, w.Body.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	var old []string
+	for p := range rd.urls {
+		old = append(old, p)
+	}
+	sort.Strings(old)
+	for _, p := range old {
+		if err := write(dir, p, []byte(refreshPage("/"+rd.urls[p]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// files returns the names of the files below dir, relative to it and with
+// slashes, except those with the extension skip.
+func files(dir, skip string) ([]string, error) {
+	var list []string
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || skip != "" && filepath.Ext(name) == skip {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		list = append(list, filepath.ToSlash(rel))
+		return nil
+	})
+	return list, err
+}
+
+// write writes the file of the URL path p below dir.
+func write(dir, p string, data []byte) error {
+	if strings.HasSuffix(p, "/") {
+		p += "index.html"
+	}
+	name := filepath.Join(dir, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, data, 0644)
+}
+
+// refreshPage returns the page that redirects to url.
+func refreshPage(url string) string {
+	u := html.EscapeString(url)
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="0; url=` + u + `">
+<link rel="canonical" href="` + u + `">
+<title>` + u + `</title>
+</head>
+<body>
+<p>本文已移至 <a href="` + u + `">` + u + `</a>。</p>
+</body>
+</html>
+`
+}
@@ -0,0 +1,168 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !appengine
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testBlog = map[string]string{
+	"content_en/slices.article": `Arrays, slices (and strings)
+26 Sep 2013
+Tags: array, slice
+
+Rob Pike
+
+* Introduction
+
+Slices are everywhere.
+
+.image slices/slice.png
+`,
+	"content_en/slices/slice.png": "png",
+	"content_en/maps.article": `Go maps in action
+6 Feb 2013
+Tags: map
+
+Andrew Gerrand
+
+* Introduction
+
+Maps are useful.
+`,
+	"content/maps.article": `Go maps 实战
+6 Feb 2013
+Tags: map
+
+Andrew Gerrand
+
+* 简介
+
+映射很有用。
+`,
+	"static/favicon.ico": "ico",
+	"redirects.json":     `{"/2013/09/slices.html": "slices"}`,
+}
+
+func TestExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range testBlog {
+		if err := write(dir, "/"+name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func(p string) { *staticPath = p }(*staticPath)
+	*staticPath = filepath.Join(dir, "static")
+
+	cfg := config
+	cfg.TemplatePath = "../template/"
+	s, err := newLangServer(cfg, filepath.Join(dir, "content_en"), filepath.Join(dir, "content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd, err := newRedirector(filepath.Join(dir, "redirects.json"), s, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", rd)
+	mux.Handle("/lib/godoc/", http.StripPrefix("/lib/godoc/", http.HandlerFunc(staticHandler)))
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(*staticPath))))
+
+	var trees [2]map[string][]byte
+	for i := range trees {
+		out := filepath.Join(dir, fmt.Sprintf("out%d", i+1))
+		if err := export(out, s, rd, mux); err != nil {
+			t.Fatal(err)
+		}
+		if trees[i], err = readTree(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, data := range trees[0] {
+		if !bytes.Equal(trees[1][name], data) {
+			t.Errorf("%s differs between two exports", name)
+		}
+	}
+	if len(trees[1]) != len(trees[0]) {
+		t.Errorf("exported %d files, then %d", len(trees[0]), len(trees[1]))
+	}
+
+	for _, name := range []string{
+		"index.html",
+		"index",
+		"feed.atom",
+		"slices.html",
+		"slices/slice.png",
+		"zh_CN/index.html",
+		"zh_CN/maps.html",
+		"zh_CN/slices.html", // untranslated
+		"zh_CN/slices/slice.png",
+		"static/favicon.ico",
+		"2013/09/slices.html",
+	} {
+		if trees[0][name] == nil {
+			t.Errorf("no %s in the export", name)
+		}
+	}
+	if page := string(trees[0]["2013/09/slices.html"]); !strings.Contains(page, `url=/slices"`) {
+		t.Errorf("redirect page:\n%s", page)
+	}
+	if page := string(trees[0]["zh_CN/slices.html"]); !strings.Contains(page, "未翻译") {
+		t.Errorf("untranslated article without the banner:\n%s", page)
+	}
+
+	if err := export(filepath.Join(dir, "out1"), s, rd, mux); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("export to a non-empty directory: %v", err)
+	}
+}
+
+// readTree returns the files below dir by their slash-separated names.
+func readTree(dir string) (map[string][]byte, error) {
+	m := make(map[string][]byte)
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(name)
+		m[filepath.ToSlash(rel)] = data
+		return err
+	})
+	return m, err
+}
+
+func TestRefreshPage(t *testing.T) {
+	page := refreshPage(`/go-maps-in-action?a=1&b="2"`)
+	const u = `/go-maps-in-action?a=1&amp;b=&#34;2&#34;`
+	for _, s := range []string{
+		`<meta http-equiv="refresh" content="0; url=` + u + `">`,
+		`<link rel="canonical" href="` + u + `">`,
+		`<a href="` + u + `">` + u + `</a>`,
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("refreshPage: no %s in\n%s", s, page)
+		}
+	}
+	if strings.Contains(page, `"2"`) {
+		t.Errorf("refreshPage: unescaped URL in\n%s", page)
+	}
+}
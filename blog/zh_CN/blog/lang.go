@@ -0,0 +1,350 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements the choice between the English and the Chinese blog.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/blog"
+)
+
+const (
+	langCookie = "lang"   // cookie holding the language chosen by the reader
+	chinese    = "zh_CN"  // the Chinese blog is served below /zh_CN/
+	zhBasePath = "/zh_CN" // BasePath of the Chinese blog
+)
+
+// A langServer serves the original English blog at / and its Chinese
+// translation at /zh_CN/.
+//
+// A page without the /zh_CN/ prefix is redirected to the Chinese one if
+// the reader prefers Chinese: a "lang" cookie, set by the ?lang=en and
+// ?lang=zh_CN links, or else the Accept-Language header says so. A Chinese
+// article that has not been translated yet, that is missing from the
+// Chinese content or still in English there, is served in English with a
+// "未翻译" banner. Every page links to its other language version with
+// hreflang links.
+//
+// Without the English content, the langServer serves the Chinese blog alone
+// at /, as the blog did before it served both, and redirects the /zh_CN/
+// paths there.
+type langServer struct {
+	en, zh    *blog.Server // en is nil without the English content
+	enContent string
+	zhContent string
+
+	// The paths of the articles, such as "/go-maps-in-action", and
+	// whether they have been translated.
+	enArticles map[string]bool
+	zhArticles map[string]bool
+}
+
+// newLangServer returns the server of the English content in enContent
+// and the Chinese content in zhContent. If enContent does not exist, it
+// serves the Chinese content alone.
+func newLangServer(cfg blog.Config, enContent, zhContent string) (*langServer, error) {
+	s := &langServer{enContent: enContent, zhContent: zhContent}
+	var err error
+	if s.zhArticles, err = articles(zhContent); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(enContent); os.IsNotExist(err) {
+		cfg.ContentPath = zhContent
+		if s.zh, err = blog.NewServer(cfg); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if s.enArticles, err = articles(enContent); err != nil {
+		return nil, err
+	}
+	cfg.ContentPath = enContent
+	if s.en, err = blog.NewServer(cfg); err != nil {
+		return nil, err
+	}
+	cfg.ContentPath = zhContent
+	cfg.BasePath = zhBasePath
+	if s.zh, err = blog.NewServer(cfg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// articles returns the paths of the articles below dir, and whether they
+// have been translated, that is whether they hold Chinese text.
+func articles(dir string) (map[string]bool, error) {
+	m := make(map[string]bool)
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(name) != ".article" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		m["/"+strings.TrimSuffix(filepath.ToSlash(rel), ".article")] = bytes.IndexFunc(data, isHan) >= 0
+		return nil
+	})
+	return m, err
+}
+
+func isHan(r rune) bool { return unicode.Is(unicode.Han, r) }
+
+func (s *langServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	if s.en == nil {
+		if p == zhBasePath || strings.HasPrefix(p, zhBasePath+"/") {
+			http.Redirect(w, r, "/"+strings.TrimPrefix(p[len(zhBasePath):], "/"), http.StatusFound)
+			return
+		}
+		s.zh.ServeHTTP(w, r)
+		return
+	}
+	if lang := r.URL.Query().Get("lang"); lang == "en" || lang == chinese {
+		// A language link: remember the choice.
+		http.SetCookie(w, &http.Cookie{Name: langCookie, Value: lang, Path: "/", MaxAge: 365 * 24 * 3600})
+		p = strings.TrimPrefix(p, zhBasePath)
+		if lang == chinese {
+			p = zhBasePath + p
+		}
+		http.Redirect(w, r, p, http.StatusFound)
+		return
+	}
+
+	if p == zhBasePath {
+		http.Redirect(w, r, zhBasePath+"/", http.StatusFound)
+		return
+	}
+	if !strings.HasPrefix(p, zhBasePath+"/") {
+		w.Header().Add("Vary", "Accept-Language, Cookie")
+		if s.isPage(p) && prefersChinese(r) {
+			http.Redirect(w, r, zhBasePath+p, http.StatusFound)
+			return
+		}
+		s.serve(w, r, s.en, p, false)
+		return
+	}
+
+	p = strings.TrimPrefix(p, zhBasePath)
+	translated, ok := s.zhArticles[p]
+	switch {
+	case ok && translated || !s.english(p) && s.exists(s.zhContent, p):
+		s.serve(w, r, s.zh, p, false)
+	case s.english(p):
+		// Not translated yet: the English article, with a banner.
+		s.serve(w, r, s.en, p, true)
+	case s.isPage(p):
+		s.serve(w, r, s.zh, p, false)
+	case s.exists(s.enContent, p):
+		// A file of an English article shown in its place.
+		r.URL.Path = p
+		s.en.ServeHTTP(w, r)
+	default:
+		s.zh.ServeHTTP(w, r)
+	}
+}
+
+// english reports whether there is an English article at the path p.
+func (s *langServer) english(p string) bool {
+	_, ok := s.enArticles[p]
+	return ok
+}
+
+// article reports whether there is an article at the path p, in English
+// or in Chinese.
+func (s *langServer) article(p string) bool {
+	_, zh := s.zhArticles[p]
+	return zh || s.english(p)
+}
+
+// slugs returns the names of the articles, such as "go-maps-in-action",
+// sorted.
+func (s *langServer) slugs() []string {
+	var list []string
+	for p := range s.enArticles {
+		list = append(list, p[1:])
+	}
+	for p := range s.zhArticles {
+		if !s.english(p) {
+			list = append(list, p[1:])
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// exists reports whether the content directory dir has the file p.
+func (s *langServer) exists(dir, p string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p)))
+	return err == nil
+}
+
+// isPage reports whether the path p, without the /zh_CN prefix, is that of
+// a page of the blog: the home page, the index or an article. The feeds and
+// the files of the articles are not.
+func (s *langServer) isPage(p string) bool {
+	return p == "/" || p == "/index" || s.article(p)
+}
+
+// serve serves the path p, without the /zh_CN prefix, with the server b. A
+// page gets the hreflang links to its language versions, and the
+// untranslated banner if untranslated is set.
+func (s *langServer) serve(w http.ResponseWriter, r *http.Request, b *blog.Server, p string, untranslated bool) {
+	if b == s.zh {
+		r.URL.Path = zhBasePath + p
+	} else {
+		r.URL.Path = p
+	}
+	if !s.isPage(p) {
+		b.ServeHTTP(w, r)
+		return
+	}
+	pw := &pageWriter{ResponseWriter: w, code: http.StatusOK}
+	b.ServeHTTP(pw, r)
+	page := pw.buf.Bytes()
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	if pw.code == http.StatusOK {
+		page = insert(page, "</head>", s.links(p), true)
+		if untranslated {
+			page = insert(page, `<div id="content">`, banner(p), false)
+		}
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+	w.WriteHeader(pw.code)
+	w.Write(page)
+}
+
+// links returns the hreflang links of the page p to its language versions.
+// They are relative to the root, as the blog is served on other hosts than
+// config.Hostname, and exported.
+func (s *langServer) links(p string) string {
+	var buf bytes.Buffer
+	if p == "/" || p == "/index" || s.english(p) {
+		fmt.Fprintf(&buf, "<link rel=\"alternate\" hreflang=\"en\" href=\"%s\">\n", html.EscapeString(p))
+	}
+	// The Chinese page exists even if it is the English one with a banner.
+	fmt.Fprintf(&buf, "<link rel=\"alternate\" hreflang=\"zh-CN\" href=\"%s%s\">\n", zhBasePath, html.EscapeString(p))
+	return buf.String()
+}
+
+// banner returns the banner of the untranslated article p.
+func banner(p string) string {
+	return fmt.Sprintf(`
+<div class="untranslated" style="margin: 20px 0; padding: 10px; background: #fffbdb; border: 1px solid #e0d78c;">
+	<b>未翻译</b>：本文尚未翻译，以下为英文原文。<a href="%s?lang=en">阅读英文版</a>
+</div>
+`, html.EscapeString(p))
+}
+
+// insert inserts s into page before the first occurrence of mark, or after
+// it if before is not set. The page is returned unchanged without mark.
+func insert(page []byte, mark, s string, before bool) []byte {
+	i := bytes.Index(page, []byte(mark))
+	if i < 0 {
+		return page
+	}
+	if !before {
+		i += len(mark)
+	}
+	var buf bytes.Buffer
+	buf.Write(page[:i])
+	buf.WriteString(s)
+	buf.Write(page[i:])
+	return buf.Bytes()
+}
+
+// A pageWriter holds a page for serve to complete.
+type pageWriter struct {
+	http.ResponseWriter
+	buf  bytes.Buffer
+	code int
+}
+
+func (w *pageWriter) WriteHeader(code int)        { w.code = code }
+func (w *pageWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
+
+// prefersChinese reports whether the reader of r prefers Chinese, by the
+// language cookie or else by the Accept-Language header.
+func prefersChinese(r *http.Request) bool {
+	if c, err := r.Cookie(langCookie); err == nil && (c.Value == "en" || c.Value == chinese) {
+		return c.Value == chinese
+	}
+	langs := acceptLanguages(r.Header.Get("Accept-Language"))
+	for _, l := range langs {
+		switch {
+		case l == "zh" || strings.HasPrefix(l, "zh-"):
+			return true
+		case l == "en" || strings.HasPrefix(l, "en-"):
+			return false
+		}
+	}
+	return false
+}
+
+// acceptLanguages returns the language tags of an Accept-Language header,
+// in lower case, most preferred first, without the ones of quality 0.
+func acceptLanguages(header string) []string {
+	var list []acceptLanguage
+	for i, part := range strings.Split(header, ",") {
+		f := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(f[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range f[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			list = append(list, acceptLanguage{tag, q, i})
+		}
+	}
+	sort.Sort(byQuality(list))
+	tags := make([]string, len(list))
+	for i, l := range list {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+type acceptLanguage struct {
+	tag   string
+	q     float64
+	index int
+}
+
+type byQuality []acceptLanguage
+
+func (s byQuality) Len() int { return len(s) }
+func (s byQuality) Less(i, j int) bool {
+	if s[i].q != s[j].q {
+		return s[i].q > s[j].q
+	}
+	return s[i].index < s[j].index
+}
+func (s byQuality) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,67 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var acceptLanguagesTests = []struct {
+	header string
+	tags   []string
+}{
+	{"", []string{}},
+	{"zh-CN", []string{"zh-cn"}},
+	{"en-US,en;q=0.8,zh-CN;q=0.6", []string{"en-us", "en", "zh-cn"}},
+	{"en;q=0.5, zh-CN;q=0.9, zh", []string{"zh", "zh-cn", "en"}},
+	{"fr;q=0.5,de;q=0.5,en;q=0.7", []string{"en", "fr", "de"}},
+	{"zh;q=0, en", []string{"en"}},
+	{"zh;q=x, en;q=0.5", []string{"zh", "en"}},
+}
+
+func TestAcceptLanguages(t *testing.T) {
+	for _, tt := range acceptLanguagesTests {
+		tags := acceptLanguages(tt.header)
+		if !reflect.DeepEqual(tags, tt.tags) {
+			t.Errorf("acceptLanguages(%q) = %q, want %q", tt.header, tags, tt.tags)
+		}
+	}
+}
+
+var prefersChineseTests = []struct {
+	cookie string
+	header string
+	zh     bool
+}{
+	{"", "", false},
+	{"", "zh-CN,zh;q=0.8", true},
+	{"", "en-US,zh-CN;q=0.8", false},
+	{"", "fr,zh-TW;q=0.8,en;q=0.5", true},
+	{"", "en;q=0.5,zh;q=0.9", true},
+	{"", "zh;q=0,en", false},
+	{"en", "zh-CN", false},
+	{"zh_CN", "en-US", true},
+	{"fr", "zh-CN", true},
+}
+
+func TestPrefersChinese(t *testing.T) {
+	for _, tt := range prefersChineseTests {
+		r, err := http.NewRequest("GET", "/slices", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: langCookie, Value: tt.cookie})
+		}
+		if tt.header != "" {
+			r.Header.Set("Accept-Language", tt.header)
+		}
+		if zh := prefersChinese(r); zh != tt.zh {
+			t.Errorf("prefersChinese(cookie %q, Accept-Language %q) = %v, want %v", tt.cookie, tt.header, zh, tt.zh)
+		}
+	}
+}
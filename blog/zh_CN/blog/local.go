@@ -12,43 +12,57 @@ import (
 	"flag"
 	"log"
 	"net/http"
-
-	"golang.org/x/tools/blog"
+	"time"
 )
 
 var (
 	httpAddr     = flag.String("http", "localhost:8080", "HTTP listen address")
 	contentPath  = flag.String("content", "content/", "path to content files")
+	enContent    = flag.String("content-en", "content_en/", "path to the English content files")
 	templatePath = flag.String("template", "template/", "path to template files")
 	staticPath   = flag.String("static", "static/", "path to static files")
-	reload       = flag.Bool("reload", false, "reload content on each page load")
+	redirects    = flag.String("redirects", redirectFile, "path to the redirects of the old blog paths")
+	reload       = flag.Bool("reload", false, "rebuild the blog when the content or the templates change")
+	exportDir    = flag.String("export", "", "write the blog as static files to this directory and exit")
 )
 
 func main() {
 	flag.Parse()
-	config.ContentPath = *contentPath
 	config.TemplatePath = *templatePath
+	s, rd, err := build()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var h http.Handler = rd
 	if *reload {
-		http.HandleFunc("/", reloadingBlogServer)
-	} else {
-		s, err := blog.NewServer(config)
-		if err != nil {
-			log.Fatal(err)
+		rebuild := func() (http.Handler, error) {
+			_, rd, err := build()
+			return rd, err
 		}
-		http.Handle("/", s)
+		h = newWatcher(rd, rebuild, time.Second, *enContent, *contentPath, *templatePath, *redirects)
 	}
+	http.Handle("/", h)
 	fs := http.FileServer(http.Dir(*staticPath))
 	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	if *exportDir != "" {
+		if err := export(*exportDir, s, rd, http.DefaultServeMux); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	log.Fatal(http.ListenAndServe(*httpAddr, nil))
 }
 
-// reloadingBlogServer is an handler that restarts the blog server on each page
-// view. Inefficient; don't enable by default. Handy when editing blog content.
-func reloadingBlogServer(w http.ResponseWriter, r *http.Request) {
-	s, err := blog.NewServer(config)
+// build returns the server of the blog and the redirector in front of it,
+// which checks the redirects against its articles.
+func build() (*langServer, *redirector, error) {
+	s, err := newLangServer(config, *enContent, *contentPath)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
-		return
+		return nil, nil, err
+	}
+	rd, err := newRedirector(*redirects, s, s)
+	if err != nil {
+		return nil, nil, err
 	}
-	s.ServeHTTP(w, r)
+	return s, rd, nil
 }
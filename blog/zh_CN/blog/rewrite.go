@@ -4,78 +4,170 @@
 
 package main
 
-import "net/http"
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
 
-// Register HTTP handlers that redirect old blog paths to their new locations.
-func init() {
-	for p := range urlMap {
-		dest := "/" + urlMap[p]
-		http.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
-			http.Redirect(w, r, dest, http.StatusMovedPermanently)
-		})
+// redirectFile maps the old blog paths, such as "/2011/03/c-go-cgo.html",
+// to the articles they moved to, such as "c-go-cgo".
+const redirectFile = "redirects.json"
+
+// A redirector redirects the old blog paths to their new locations, and
+// counts the redirects. The counts are served at /.redirects. An unknown
+// old path gets a page suggesting the nearest article.
+type redirector struct {
+	next  http.Handler
+	urls  map[string]string // old path to article
+	slugs []string          // the articles, to suggest one
+
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+var oldPathRx = regexp.MustCompile(`^/[0-9]{4}/[0-9]{2}/([^/]+)\.html$`)
+
+// newRedirector returns the redirector of the redirect file name in front
+// of next. Every redirect must lead to an article of s.
+func newRedirector(name string, s *langServer, next http.Handler) (*redirector, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	rd := &redirector{next: next, slugs: s.slugs(), hits: make(map[string]int)}
+	if err := json.Unmarshal(data, &rd.urls); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	var bad []string
+	for old, slug := range rd.urls {
+		if !s.article("/" + slug) {
+			bad = append(bad, old+" -> "+slug)
+		}
+	}
+	if len(bad) > 0 {
+		sort.Strings(bad)
+		return nil, fmt.Errorf("%s: no article for the redirects:\n\t%s", name, strings.Join(bad, "\n\t"))
 	}
+	return rd, nil
 }
 
-var urlMap = map[string]string{
-	"/2010/03/go-whats-new-in-march-2010.html":               "go-whats-new-in-march-2010",
-	"/2010/04/json-rpc-tale-of-interfaces.html":              "json-rpc-tale-of-interfaces",
-	"/2010/04/third-party-libraries-goprotobuf-and.html":     "third-party-libraries-goprotobuf-and",
-	"/2010/05/go-at-io-frequently-asked-questions.html":      "go-at-io-frequently-asked-questions",
-	"/2010/05/new-talk-and-tutorials.html":                   "new-talk-and-tutorials",
-	"/2010/05/upcoming-google-io-go-events.html":             "upcoming-google-io-go-events",
-	"/2010/06/go-programming-session-video-from.html":        "go-programming-session-video-from",
-	"/2010/07/gos-declaration-syntax.html":                   "gos-declaration-syntax",
-	"/2010/07/share-memory-by-communicating.html":            "share-memory-by-communicating",
-	"/2010/08/defer-panic-and-recover.html":                  "defer-panic-and-recover",
-	"/2010/09/go-concurrency-patterns-timing-out-and.html":   "go-concurrency-patterns-timing-out-and",
-	"/2010/09/go-wins-2010-bossie-award.html":                "go-wins-2010-bossie-award",
-	"/2010/09/introducing-go-playground.html":                "introducing-go-playground",
-	"/2010/10/real-go-projects-smarttwitter-and-webgo.html":  "real-go-projects-smarttwitter-and-webgo",
-	"/2010/11/debugging-go-code-status-report.html":          "debugging-go-code-status-report",
-	"/2010/11/go-one-year-ago-today.html":                    "go-one-year-ago-today",
-	"/2011/01/go-slices-usage-and-internals.html":            "go-slices-usage-and-internals",
-	"/2011/01/json-and-go.html":                              "json-and-go",
-	"/2011/03/c-go-cgo.html":                                 "c-go-cgo",
-	"/2011/03/go-becomes-more-stable.html":                   "go-becomes-more-stable",
-	"/2011/03/gobs-of-data.html":                             "gobs-of-data",
-	"/2011/03/godoc-documenting-go-code.html":                "godoc-documenting-go-code",
-	"/2011/04/go-at-heroku.html":                             "go-at-heroku",
-	"/2011/04/introducing-gofix.html":                        "introducing-gofix",
-	"/2011/05/gif-decoder-exercise-in-go-interfaces.html":    "gif-decoder-exercise-in-go-interfaces",
-	"/2011/05/go-and-google-app-engine.html":                 "go-and-google-app-engine",
-	"/2011/05/go-at-google-io-2011-videos.html":              "go-at-google-io-2011-videos",
-	"/2011/06/first-class-functions-in-go-and-new-go.html":   "first-class-functions-in-go-and-new-go",
-	"/2011/06/profiling-go-programs.html":                    "profiling-go-programs",
-	"/2011/06/spotlight-on-external-go-libraries.html":       "spotlight-on-external-go-libraries",
-	"/2011/07/error-handling-and-go.html":                    "error-handling-and-go",
-	"/2011/07/go-for-app-engine-is-now-generally.html":       "go-for-app-engine-is-now-generally",
-	"/2011/09/go-image-package.html":                         "go-image-package",
-	"/2011/09/go-imagedraw-package.html":                     "go-imagedraw-package",
-	"/2011/09/laws-of-reflection.html":                       "laws-of-reflection",
-	"/2011/09/two-go-talks-lexical-scanning-in-go-and.html":  "two-go-talks-lexical-scanning-in-go-and",
-	"/2011/10/debugging-go-programs-with-gnu-debugger.html":  "debugging-go-programs-with-gnu-debugger",
-	"/2011/10/go-app-engine-sdk-155-released.html":           "go-app-engine-sdk-155-released",
-	"/2011/10/learn-go-from-your-browser.html":               "learn-go-from-your-browser",
-	"/2011/10/preview-of-go-version-1.html":                  "preview-of-go-version-1",
-	"/2011/11/go-programming-language-turns-two.html":        "go-programming-language-turns-two",
-	"/2011/11/writing-scalable-app-engine.html":              "writing-scalable-app-engine",
-	"/2011/12/building-stathat-with-go.html":                 "building-stathat-with-go",
-	"/2011/12/from-zero-to-go-launching-on-google.html":      "from-zero-to-go-launching-on-google",
-	"/2011/12/getting-to-know-go-community.html":             "getting-to-know-go-community",
-	"/2012/03/go-version-1-is-released.html":                 "go-version-1-is-released",
-	"/2012/07/gccgo-in-gcc-471.html":                         "gccgo-in-gcc-471",
-	"/2012/07/go-videos-from-google-io-2012.html":            "go-videos-from-google-io-2012",
-	"/2012/08/go-updates-in-app-engine-171.html":             "go-updates-in-app-engine-171",
-	"/2012/08/organizing-go-code.html":                       "organizing-go-code",
-	"/2012/11/go-turns-three.html":                           "go-turns-three",
-	"/2013/01/concurrency-is-not-parallelism.html":           "concurrency-is-not-parallelism",
-	"/2013/01/go-fmt-your-code.html":                         "go-fmt-your-code",
-	"/2013/01/the-app-engine-sdk-and-workspaces-gopath.html": "the-app-engine-sdk-and-workspaces-gopath",
-	"/2013/01/two-recent-go-talks.html":                      "two-recent-go-talks",
-	"/2013/02/getthee-to-go-meetup.html":                     "getthee-to-go-meetup",
-	"/2013/02/go-maps-in-action.html":                        "go-maps-in-action",
-	"/2013/03/two-recent-go-articles.html":                   "two-recent-go-articles",
-	"/2013/03/the-path-to-go-1.html":                         "the-path-to-go-1",
-	"/2013/05/go-11-is-released.html":                        "go-11-is-released.article",
-	"/2013/05/advanced-go-concurrency-patterns.html":         "advanced-go-concurrency-patterns.article",
+func (rd *redirector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	if p == "/.redirects" {
+		rd.serveHits(w)
+		return
+	}
+	if slug, ok := rd.urls[p]; ok {
+		rd.mu.Lock()
+		rd.hits[p]++
+		n := rd.hits[p]
+		rd.mu.Unlock()
+		log.Printf("redirect %s to /%s (%d)", p, slug, n)
+		http.Redirect(w, r, "/"+slug, http.StatusMovedPermanently)
+		return
+	}
+	if m := oldPathRx.FindStringSubmatch(p); m != nil {
+		rd.serveUnknown(w, p, m[1])
+		return
+	}
+	rd.next.ServeHTTP(w, r)
+}
+
+// serveHits serves the counts of the redirects, most used first.
+func (rd *redirector) serveHits(w http.ResponseWriter) {
+	rd.mu.Lock()
+	var list []hit
+	for p, n := range rd.hits {
+		list = append(list, hit{p, n})
+	}
+	rd.mu.Unlock()
+	sort.Sort(byHits(list))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, h := range list {
+		fmt.Fprintf(w, "%d\t%s\t/%s\n", h.n, h.path, rd.urls[h.path])
+	}
+}
+
+type hit struct {
+	path string
+	n    int
+}
+
+type byHits []hit
+
+func (s byHits) Len() int { return len(s) }
+func (s byHits) Less(i, j int) bool {
+	if s[i].n != s[j].n {
+		return s[i].n > s[j].n
+	}
+	return s[i].path < s[j].path
+}
+func (s byHits) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// serveUnknown serves the page of the unknown old path p, with the article
+// nearest to its slug, if any.
+func (rd *redirector) serveUnknown(w http.ResponseWriter, p, slug string) {
+	log.Printf("unknown old path %s", p)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>找不到页面</title>\n</head>\n<body>\n")
+	fmt.Fprintf(w, "<p>找不到 %s。</p>\n", html.EscapeString(p))
+	if s := nearest(slug, rd.slugs); s != "" {
+		fmt.Fprintf(w, "<p>您要找的是不是 <a href=\"/%s\">%s</a>？</p>\n", html.EscapeString(s), html.EscapeString(s))
+	}
+	fmt.Fprintf(w, "<p><a href=\"/index\">博客索引</a></p>\n</body>\n</html>\n")
+}
+
+// nearest returns the slug nearest to s, or "" if none is near enough. The
+// old slugs are often the start of the new ones, which are compared cut to
+// the length of s too.
+func nearest(s string, slugs []string) string {
+	best, min := "", len(s)/3+1
+	for _, slug := range slugs {
+		d := distance(s, slug)
+		if len(slug) > len(s) {
+			if d2 := distance(s, slug[:len(s)]); d2 < d {
+				d = d2
+			}
+		}
+		if d < min {
+			best, min = slug, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
 }
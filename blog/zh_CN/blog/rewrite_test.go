@@ -0,0 +1,56 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+var distanceTests = []struct {
+	a, b string
+	d    int
+}{
+	{"", "", 0},
+	{"", "abc", 3},
+	{"abc", "", 3},
+	{"maps", "maps", 0},
+	{"kitten", "sitting", 3},
+	{"go-maps", "go-map", 1},
+	{"gob", "gobs", 1},
+}
+
+func TestDistance(t *testing.T) {
+	for _, tt := range distanceTests {
+		if d := distance(tt.a, tt.b); d != tt.d {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, d, tt.d)
+		}
+	}
+}
+
+var testSlugs = []string{
+	"c-go-cgo",
+	"defer-panic-and-recover",
+	"go-maps-in-action",
+	"go-slices-usage-and-internals",
+	"gobs-of-data",
+}
+
+var nearestTests = []struct {
+	s, slug string
+}{
+	{"gobs-of-data", "gobs-of-data"},
+	{"go-slices-usage-and", "go-slices-usage-and-internals"}, // a start
+	{"defer-panic-and-recovr", "defer-panic-and-recover"},
+	{"c-go-cg", "c-go-cgo"},
+	{"go-maps", "go-maps-in-action"},
+	{"json-and-go", ""},
+	{"x", ""},
+}
+
+func TestNearest(t *testing.T) {
+	for _, tt := range nearestTests {
+		if slug := nearest(tt.s, testSlugs); slug != tt.slug {
+			t.Errorf("nearest(%q) = %q, want %q", tt.s, slug, tt.slug)
+		}
+	}
+}
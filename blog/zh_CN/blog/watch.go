@@ -0,0 +1,118 @@
+// Copyright 2015 The Go Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !appengine
+
+// This file implements the rebuilding of the blog when its files change.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A watcher serves the blog of its last good build, and rebuilds it in the
+// background when its files change, which it polls. If a build fails, it
+// goes on serving the last good one, with the error shown over the pages,
+// until a build succeeds again.
+type watcher struct {
+	build func() (http.Handler, error)
+	files []string
+
+	mu     sync.Mutex
+	server http.Handler
+	err    error
+}
+
+// newWatcher returns the watcher of the files, and of the files below the
+// directories among them, which polls them every interval and rebuilds the
+// blog with build, starting from server.
+func newWatcher(server http.Handler, build func() (http.Handler, error), interval time.Duration, files ...string) *watcher {
+	w := &watcher{build: build, files: files, server: server}
+	go w.poll(w.stamp(), interval)
+	return w
+}
+
+// poll rebuilds the blog whenever the stamp of the files changes from last.
+func (w *watcher) poll(last string, interval time.Duration) {
+	for range time.Tick(interval) {
+		stamp := w.stamp()
+		if stamp == last {
+			continue
+		}
+		last = stamp
+		s, err := w.build()
+		w.mu.Lock()
+		if err != nil {
+			log.Printf("rebuild: %v", err)
+			w.err = err
+		} else {
+			log.Printf("rebuilt the blog")
+			w.server, w.err = s, nil
+		}
+		w.mu.Unlock()
+	}
+}
+
+// stamp returns a summary of the names, sizes and modification times of the
+// files, which changes when they do.
+func (w *watcher) stamp() string {
+	var buf []byte
+	for _, root := range w.files {
+		filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				buf = append(buf, err.Error()...)
+				return nil
+			}
+			buf = append(buf, name...)
+			buf = append(buf, ' ')
+			buf = strconv.AppendInt(buf, info.Size(), 10)
+			buf = append(buf, ' ')
+			buf = strconv.AppendInt(buf, info.ModTime().UnixNano(), 10)
+			buf = append(buf, '\n')
+			return nil
+		})
+	}
+	return string(buf)
+}
+
+func (w *watcher) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	s, err := w.server, w.err
+	w.mu.Unlock()
+	if err == nil {
+		s.ServeHTTP(rw, r)
+		return
+	}
+	pw := &pageWriter{ResponseWriter: rw, code: http.StatusOK}
+	s.ServeHTTP(pw, r)
+	page := pw.buf.Bytes()
+	if ct := rw.Header().Get("Content-Type"); strings.HasPrefix(ct, "text/html") ||
+		ct == "" && strings.HasPrefix(http.DetectContentType(page), "text/html") {
+		page = insert(page, "</body>", overlay(err), true)
+		rw.Header().Set("Content-Length", strconv.Itoa(len(page)))
+	}
+	rw.WriteHeader(pw.code)
+	rw.Write(page)
+}
+
+// overlay returns the box that shows the error of the last build over a
+// page.
+func overlay(err error) string {
+	return fmt.Sprintf(`
+<div style="position: fixed; left: 0; right: 0; bottom: 0; max-height: 50%%; overflow: auto; z-index: 1000; margin: 0; padding: 10px 20px; background: #fdd; border-top: 2px solid #c00;">
+	<b>重新生成博客失败, 当前显示的是上一次成功生成的版本:</b>
+	<pre style="white-space: pre-wrap;">%s</pre>
+</div>
+`, html.EscapeString(err.Error()))
+}
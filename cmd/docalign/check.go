@@ -0,0 +1,87 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+// A problem is something wrong with a document, at a line.
+type problem struct {
+	line int
+	msg  string
+}
+
+type problems []problem
+
+func (p *problems) report(line int, format string, args ...interface{}) {
+	*p = append(*p, problem{line, fmt.Sprintf(format, args...)})
+}
+
+// checkPairs checks that each English block has a Chinese counterpart of
+// the same elements, with the same <pre> code.
+func checkPairs(doc *htmldoc.Document) problems {
+	var probs problems
+	for _, p := range doc.Pairs {
+		for i, en := range p.English {
+			if i >= len(p.Chinese) {
+				probs.report(en.Line, "English <%s> has no Chinese counterpart", en.Tag)
+				continue
+			}
+			zh := p.Chinese[i]
+			if zh.Tag != en.Tag {
+				probs.report(zh.Line, "Chinese <%s> for the English <%s> at line %d", zh.Tag, en.Tag, en.Line)
+				continue
+			}
+			if en.Tag == "pre" {
+				if n, enLine, zhLine := diffLines(en.Inner(), zh.Inner()); n >= 0 {
+					probs.report(zh.Line+n, "<pre> differs from the English one at line %d: %q, want %q", en.Line, zhLine, enLine)
+				}
+			}
+		}
+	}
+	return probs
+}
+
+// diffLines returns the index of the first line where the texts a and b
+// differ, and the lines, or -1 if they are the same.
+func diffLines(a, b string) (int, string, string) {
+	if a == b {
+		return -1, "", ""
+	}
+	al := strings.Split(a, "\n")
+	bl := strings.Split(b, "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(al):
+			return i, "", bl[i]
+		case i >= len(bl):
+			return i, al[i], ""
+		case al[i] != bl[i]:
+			return i, al[i], bl[i]
+		}
+	}
+}
+
+// checkIDs checks that the ids of the elements are unique.
+func checkIDs(doc *htmldoc.Document) problems {
+	var probs problems
+	seen := make(map[string]int) // line of each id
+	doc.Walk(func(e *htmldoc.Element) bool {
+		if e.ID == "" {
+			return true
+		}
+		if line, ok := seen[e.ID]; ok {
+			probs.report(e.Line, "duplicate id %q, first at line %d", e.ID, line)
+		} else {
+			seen[e.ID] = e.Line
+		}
+		return true
+	})
+	return probs
+}
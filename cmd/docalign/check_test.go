@@ -0,0 +1,125 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+const testDoc = `<div class="english">
+<h2 id="intro">Introduction</h2>
+</div>
+
+<h2 id="intro">引言</h2>
+
+<div class="english">
+<p>
+Print it:
+</p>
+<pre>
+fmt.Println(x) // Print x.
+</pre>
+</div>
+
+<p>
+打印它：
+</p>
+<pre>
+fmt.Println(x) // 打印 x。
+</pre>
+
+<div class="english">
+<p>
+A paragraph.
+</p>
+</div>
+
+<h3>一段</h3>
+
+<div class="english">
+<p>
+Another paragraph.
+</p>
+</div>
+
+<p>
+Left in English.
+</p>
+`
+
+func messages(probs problems) []string {
+	var list []string
+	for _, p := range probs {
+		list = append(list, p.msg)
+	}
+	return list
+}
+
+func TestCheck(t *testing.T) {
+	doc := htmldoc.Parse("test.html", []byte(testDoc))
+	for _, tt := range []struct {
+		name  string
+		probs problems
+		want  []string
+	}{
+		{"pairs", checkPairs(doc), []string{
+			`<pre> differs from the English one at line 11: "fmt.Println(x) // 打印 x。", want "fmt.Println(x) // Print x."`,
+			"Chinese <h3> for the English <p> at line 24",
+		}},
+		{"ids", checkIDs(doc), []string{
+			`duplicate id "intro", first at line 2`,
+		}},
+	} {
+		if got := messages(tt.probs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if p := checkPairs(doc)[0]; p.line != 20 {
+		t.Errorf("<pre> problem at line %d, want 20", p.line)
+	}
+}
+
+const testUpstream = `<h2 id="intro">Introduction</h2>
+
+<p>
+Print it:
+</p>
+<pre>
+fmt.Println(x) // Print x.
+</pre>
+
+<p>
+A paragraph.
+</p>
+
+<p>
+Added upstream.
+</p>
+
+<p>
+Another paragraph, changed upstream.
+</p>
+
+<ul>
+<li>A list.
+</ul>
+`
+
+func TestDiff(t *testing.T) {
+	doc := htmldoc.Parse("test.html", []byte(testDoc))
+	up := htmldoc.Parse("up.html", []byte(testUpstream))
+	got := diff(doc, up)
+	want := problems{
+		{32, "<p> changed upstream (up.html:18): Another paragraph, changed upstream."},
+		{32, "<p> added upstream (up.html:14): Added upstream."},
+		{32, "<li> added upstream (up.html:23): A list."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diff:\ngot  %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docalign checks that the bilingual HTML documents of doc/zh_CN keep their
+// English and Chinese paragraphs aligned, see package htmldoc.
+//
+// For each document it checks that
+//
+//   - every <div class="english"> block is followed by a Chinese counterpart
+//     of the same elements, a <p> for a <p>, an <h2> for an <h2>, and so on;
+//   - each <pre> of the Chinese counterpart is that of the English block,
+//     unchanged;
+//   - no two elements have the same id, since links could only reach one.
+//
+// Usage:
+//
+//	docalign [flags] [path ...]
+//
+// The paths, by default doc/zh_CN, are HTML documents or directories, which
+// are searched for documents. Docalign exits with status 1 if there are
+// problems. The flags are:
+//
+//	-sync dir
+//		compare the English text with the upstream English documents in dir
+//
+// With -sync, docalign does not check the documents, but compares the English
+// text they keep with that of the upstream documents of the same names in
+// dir, such as $GOROOT/doc, and lists the paragraphs added, removed or
+// changed upstream since the translation, at the line of the document where
+// they belong. Documents without an upstream document are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+var syncDir = flag.String("sync", "", "compare with the upstream English documents in `dir`")
+
+var defaultPaths = []string{"doc/zh_CN"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docalign [flags] [path ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docalign: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+
+	found := false
+	for _, path := range paths {
+		err := walk(path, func(name, rel string) error {
+			probs, err := check(name, rel)
+			if err != nil {
+				return err
+			}
+			sort.Stable(byLine(probs))
+			for _, p := range probs {
+				fmt.Printf("%s:%d: %s\n", name, p.line, p.msg)
+				found = true
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if found {
+		os.Exit(1)
+	}
+}
+
+// walk calls fn for the HTML documents of path, with their names relative
+// to path, or their base names if path is a document.
+func walk(path string, fn func(name, rel string) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fn(path, filepath.Base(path))
+	}
+	return filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(name) != ".html" {
+			return err
+		}
+		rel, err := filepath.Rel(path, name)
+		if err != nil {
+			return err
+		}
+		return fn(name, rel)
+	})
+}
+
+// check returns the problems of the document, or its differences with the
+// upstream document with -sync.
+func check(name, rel string) (problems, error) {
+	src, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	doc := htmldoc.Parse(name, src)
+	if *syncDir == "" {
+		return append(checkPairs(doc), checkIDs(doc)...), nil
+	}
+	upName := filepath.Join(*syncDir, rel)
+	upSrc, err := ioutil.ReadFile(upName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return diff(doc, htmldoc.Parse(upName, upSrc)), nil
+}
+
+type byLine problems
+
+func (s byLine) Len() int           { return len(s) }
+func (s byLine) Less(i, j int) bool { return s[i].line < s[j].line }
+func (s byLine) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,174 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+// A block is a paragraph, heading, code block or list item of English text.
+type block struct {
+	*htmldoc.Element
+	text string
+}
+
+// containers are the elements whose children are compared rather than the
+// elements themselves, so that a list translated item by item compares
+// with the list upstream.
+var containers = map[string]bool{"div": true, "ul": true, "ol": true, "dl": true}
+
+// blocks returns the blocks of English text of the document: the elements
+// of its English blocks and the elements left untranslated, but not the
+// Chinese ones.
+func blocks(doc *htmldoc.Document, translated bool) []block {
+	chinese := make(map[*htmldoc.Element]bool)
+	for _, p := range doc.Pairs {
+		for _, e := range p.Chinese {
+			chinese[e] = true
+		}
+	}
+	var list []block
+	doc.Walk(func(e *htmldoc.Element) bool {
+		switch {
+		case e.Tag == "" || e.Tag == "!--" || e.Tag == "script" || e.Tag == "style" || chinese[e]:
+			return false
+		case containers[e.Tag]:
+			return true
+		}
+		text := e.Text()
+		if text == "" || translated && e.Tag != "pre" && hasHan(text) {
+			return false
+		}
+		list = append(list, block{e, e.Tag + " " + text})
+		return false
+	})
+	return list
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// diff compares the English blocks of the document with those of the
+// upstream document, and reports the blocks added, removed or changed
+// upstream, at the line of the document where they belong.
+func diff(doc, up *htmldoc.Document) problems {
+	a := blocks(doc, true)
+	b := blocks(up, false)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i].text == b[j].text:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var probs problems
+	var removed, added []block
+	// flush reports the blocks of a hunk, before the block of the document
+	// at line.
+	flush := func(line int) {
+		for _, x := range removed {
+			if k := similar(added, x); k >= 0 {
+				probs.report(x.Line, "<%s> changed upstream (%s:%d): %s", x.Tag, up.Name, added[k].Line, snippet(added[k]))
+				added = append(added[:k], added[k+1:]...)
+				continue
+			}
+			probs.report(x.Line, "<%s> removed upstream: %s", x.Tag, snippet(x))
+		}
+		for _, y := range added {
+			probs.report(line, "<%s> added upstream (%s:%d): %s", y.Tag, up.Name, y.Line, snippet(y))
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].text == b[j].text:
+			flush(a[i].Line)
+			i++
+			j++
+		case j >= len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+	line := 1
+	if len(a) > 0 {
+		line = a[len(a)-1].Line
+	}
+	flush(line)
+	return probs
+}
+
+// similar returns the index of the block of list with the tag of x that is
+// most like x, sharing at least half of their words, or -1.
+func similar(list []block, x block) int {
+	best, k := 0.5, -1
+	for i, y := range list {
+		if y.Tag != x.Tag {
+			continue
+		}
+		if s := likeness(x.text, y.text); s >= best {
+			best, k = s, i
+		}
+	}
+	return k
+}
+
+// likeness returns the number of words shared by a and b, divided by the
+// number of distinct words of both.
+func likeness(a, b string) float64 {
+	words := make(map[string]int)
+	for _, w := range strings.FieldsFunc(a, notWord) {
+		words[w] |= 1
+	}
+	for _, w := range strings.FieldsFunc(b, notWord) {
+		words[w] |= 2
+	}
+	shared := 0
+	for _, m := range words {
+		if m == 3 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(words))
+}
+
+func notWord(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }
+
+// snippet returns the beginning of the text of the block.
+func snippet(x block) string {
+	const max = 60
+	text := x.text[len(x.Tag)+1:]
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "…"
+}
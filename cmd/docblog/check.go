@@ -0,0 +1,211 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/presentdoc"
+)
+
+// blogHost is the host of the links to the blog.
+const blogHost = "blog.golang.org"
+
+// The images, which an article must show or link to.
+var imageExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true}
+
+// A blog holds what the references of the articles may lead to, and the
+// problems found.
+type blog struct {
+	dir       string
+	slugs     map[string]bool   // the articles, such as "pipelines"
+	redirects map[string]string // the old paths, such as "/2011/03/c-go-cgo.html"
+	used      map[string]bool   // the files of dir referenced, such as "pipelines/serial.go"
+	probs     []string
+}
+
+// check returns the problems of the references of the articles of the
+// content directory dir, as "file:line: message" lines, and the images of
+// dir that no article references, as "file: message" lines. The articles of
+// enDir and the old paths of redirectFile are valid link targets.
+func check(dir, enDir, redirectFile string) ([]string, error) {
+	b := &blog{dir: dir, slugs: make(map[string]bool), used: make(map[string]bool)}
+	var names []string
+	for _, d := range []string{dir, enDir} {
+		list, err := filepath.Glob(filepath.Join(d, "*.article"))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range list {
+			b.slugs[strings.TrimSuffix(filepath.Base(name), ".article")] = true
+		}
+		if d == dir {
+			names = list
+		}
+	}
+	data, err := ioutil.ReadFile(redirectFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b.redirects); err != nil {
+		return nil, fmt.Errorf("%s: %v", redirectFile, err)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := b.article(name); err != nil {
+			return b.probs, err
+		}
+	}
+	return b.probs, b.orphans()
+}
+
+func (b *blog) report(pos, format string, args ...interface{}) {
+	b.probs = append(b.probs, pos+": "+fmt.Sprintf(format, args...))
+}
+
+// linkRx matches a link, [[url]] or [[url][text]], with its target and its
+// text, if any.
+var linkRx = regexp.MustCompile(`\[\[([^\]]+)\](?:\[([^\]]*)\])?`)
+
+// article checks the references of the article name.
+func (b *blog) article(name string) error {
+	src, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	doc := presentdoc.Parse(name, src)
+	for _, blk := range doc.Blocks {
+		for i, line := range strings.SplitAfter(blk.Src, "\n") {
+			pos := fmt.Sprintf("%s:%d", name, blk.Line+i)
+			switch {
+			case blk.IsText():
+				for _, m := range linkRx.FindAllStringSubmatch(line, -1) {
+					b.link(pos, m[1])
+					// An image linking to its full size:
+					// [[url][.image file]].
+					if text := strings.TrimSpace(m[2]); strings.HasPrefix(text, ".image") {
+						b.directive(pos, text)
+					}
+				}
+			case strings.HasPrefix(line, "."):
+				b.directive(pos, line)
+			}
+		}
+	}
+	for _, c := range doc.Code() {
+		pos := fmt.Sprintf("%s:%d", name, c.Line)
+		b.use(c.File)
+		src, err := ioutil.ReadFile(filepath.Join(b.dir, filepath.FromSlash(c.File)))
+		if err == nil {
+			_, err = c.Select(src)
+		}
+		if err != nil {
+			b.report(pos, ".%s %s: %v", c.Cmd, c.File, err)
+		}
+	}
+	return nil
+}
+
+// directive checks the file of an .image, .iframe or .html directive.
+func (b *blog) directive(pos, line string) {
+	f := strings.Fields(line)
+	switch f[0] {
+	case ".image", ".iframe", ".html":
+		if len(f) < 2 {
+			b.report(pos, "%s: no file", f[0])
+			return
+		}
+		if u, err := url.Parse(f[1]); err == nil && (u.Scheme != "" || u.Host != "") {
+			return // another site
+		}
+		b.file(pos, f[0]+" "+f[1], f[1])
+	}
+}
+
+// link checks the link to target. The links to the blog are those to its
+// host and the rooted ones with one element, such as "/pipelines", or whose
+// first element is a directory of the content; the other rooted links, such
+// as "/pkg/fmt/", lead to golang.org.
+func (b *blog) link(pos, target string) {
+	u, err := url.Parse(target)
+	switch {
+	case err != nil:
+		b.report(pos, "link %s: %v", target, err)
+	case u.Host == blogHost:
+		b.blogPath(pos, target, u.Path)
+	case u.Scheme != "" || u.Host != "" || u.Path == "":
+		// Another site, or a fragment of the article.
+	case !strings.HasPrefix(u.Path, "/"):
+		b.file(pos, "link "+target, u.Path)
+	default:
+		elems := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if fi, err := os.Stat(filepath.Join(b.dir, elems[0])); len(elems) == 1 || err == nil && fi.IsDir() {
+			b.blogPath(pos, target, u.Path)
+		}
+	}
+}
+
+// blogPath checks the link to target, whose path on the blog is p.
+func (b *blog) blogPath(pos, target, p string) {
+	if p == "/zh_CN" || strings.HasPrefix(p, "/zh_CN/") {
+		p = p[len("/zh_CN"):]
+	}
+	_, old := b.redirects[p]
+	switch {
+	case p == "" || p == "/" || p == "/index" || p == "/feed.atom" || p == "/.json":
+	case b.slugs[p[1:]] || old:
+	case b.exists(p[1:]):
+		b.use(p[1:])
+	default:
+		b.report(pos, "link %s: no article %s", target, p)
+	}
+}
+
+// file checks that the file name of the content directory, the target of
+// the reference ref, exists.
+func (b *blog) file(pos, ref, name string) {
+	b.use(name)
+	if !b.exists(name) {
+		b.report(pos, "%s: no such file", ref)
+	}
+}
+
+func (b *blog) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(b.dir, filepath.FromSlash(name)))
+	return err == nil
+}
+
+// use records that the file name of the content directory is referenced.
+func (b *blog) use(name string) {
+	b.used[path.Clean(name)] = true
+}
+
+// orphans reports the images of the content directory that no article
+// references.
+func (b *blog) orphans() error {
+	return filepath.Walk(b.dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !imageExts[strings.ToLower(filepath.Ext(name))] {
+			return err
+		}
+		rel, err := filepath.Rel(b.dir, name)
+		if err != nil {
+			return err
+		}
+		if !b.used[filepath.ToSlash(rel)] {
+			b.probs = append(b.probs, name+": image not used by any article")
+		}
+		return nil
+	})
+}
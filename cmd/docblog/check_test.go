@@ -0,0 +1,91 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testArticle = `切片
+21 Jan 2015
+
+Rob Pike
+
+* 简介
+
+参见 [[http://blog.golang.org/strings][字符串]]、[[/pipelines]]、
+[[//blog.golang.org/no-such-article]] 和 [[/zh_CN/2011/03/c-go-cgo.html][旧文章]],
+以及 [[slices/prog.go][程序]]、[[slices/missing.go]] 和 [[/pkg/fmt/][fmt]]。
+[[http://example.com/big.png][.image slices/linked.png ]] [[slices/big.png][.image slices/gone.png]]
+
+.image slices/used.png
+.image slices/missing.png _ 300
+.iframe http://www.youtube.com/embed/xxx 309 550
+
+.code slices/prog.go /START/,/END/
+.code slices/prog.go /NOPE/
+.play slices/prog.go /START/,/END/ HLx
+`
+
+const testProg = `package main
+
+// START OMIT
+func main() {
+	println("x") // HLx
+}
+
+// END OMIT
+`
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docblog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	content := filepath.Join(dir, "content")
+	en := filepath.Join(dir, "content_en")
+	files := map[string]string{
+		"content/slices.article":       testArticle,
+		"content/slices/prog.go":       testProg,
+		"content/slices/used.png":      "",
+		"content/slices/linked.png":    "",
+		"content/slices/big.png":       "",
+		"content/slices/orphan.png":    "",
+		"content/strings.article":      "Strings\n",
+		"content_en/pipelines.article": "Pipelines\n",
+		"redirects.json":               `{"/2011/03/c-go-cgo.html": "strings"}`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	probs, err := check(content, en, filepath.Join(dir, "redirects.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(content, "slices.article")
+	want := []string{
+		name + ":9: link //blog.golang.org/no-such-article: no article /no-such-article",
+		name + ":10: link slices/missing.go: no such file",
+		name + ":11: .image slices/gone.png: no such file",
+		name + ":14: .image slices/missing.png: no such file",
+		name + ":18: .code slices/prog.go: address /NOPE/: no match for /NOPE/",
+		filepath.Join(content, "slices", "orphan.png") + ": image not used by any article",
+	}
+	if !reflect.DeepEqual(probs, want) {
+		t.Errorf("check:\n%q\nwant:\n%q", probs, want)
+	}
+}
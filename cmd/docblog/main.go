@@ -0,0 +1,82 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docblog checks the references of the articles of the blog, which a
+// translation easily breaks and which only show as blank boxes or dead links
+// in the rendered pages.
+//
+// For each .article file of the content directory it checks that
+//
+//   - the files of the .image, .iframe and .html directives exist, the
+//     iframes of other sites, such as the YouTube videos, left aside, as do
+//     those of the images shown as links, [[url][.image file.png]];
+//   - the programs of the .code and .play directives exist, their addresses
+//     select lines of them, such as the lines between the /START/ and /END/
+//     comments, and the lines have the HL marker to highlight, if any;
+//   - the links to the blog, such as [[http://blog.golang.org/slices]] or
+//     [[/pipelines][pipelines]], lead to an article, English or Chinese, to
+//     an old path of the redirect file, such as /2011/03/c-go-cgo.html, or to
+//     a file of the content directory;
+//   - the links relative to the article, such as [[pipelines/serial.go]],
+//     lead to a file of the content directory.
+//
+// It then reports the images of the content directory, such as
+// go-imagedraw-package_2a.png, that no article shows or links to.
+//
+// Usage:
+//
+//	docblog [flags]
+//
+// Docblog exits with status 1 if there are problems. The flags are:
+//
+//	-content dir
+//		content directory of the Chinese blog (default "blog/zh_CN/content")
+//	-content-en dir
+//		content directory of the English blog, whose articles the
+//		Chinese blog shows until they are translated
+//		(default "blog/zh_CN/content_en")
+//	-redirects file
+//		redirects of the old blog paths
+//		(default "blog/zh_CN/redirects.json")
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+var (
+	contentDir   = flag.String("content", "blog/zh_CN/content", "content `dir`ectory of the Chinese blog")
+	enContentDir = flag.String("content-en", "blog/zh_CN/content_en", "content `dir`ectory of the English blog")
+	redirectFile = flag.String("redirects", "blog/zh_CN/redirects.json", "redirects of the old blog paths")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docblog [flags]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docblog: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 {
+		usage()
+	}
+
+	probs, err := check(*contentDir, *enContentDir, *redirectFile)
+	for _, p := range probs {
+		fmt.Println(p)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(probs) > 0 {
+		os.Exit(1)
+	}
+}
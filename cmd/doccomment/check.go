@@ -0,0 +1,128 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/golang-china/golangdoc.translations/gocomment"
+)
+
+// check returns the untranslated and the orphan comments of the files, as
+// "file:line: message" lines. The files without Chinese comments are
+// skipped, unless all is set.
+func check(files []*gocomment.File, all bool) []string {
+	var probs []string
+	for _, f := range files {
+		if !all && !started(f) {
+			continue
+		}
+		for _, c := range f.Comments {
+			var msg string
+			switch {
+			case c.Pair != nil:
+				continue
+			case c.Lang == gocomment.English:
+				msg = "untranslated comment"
+			default:
+				msg = "Chinese comment without the English original"
+			}
+			probs = append(probs, fmt.Sprintf("%s:%d: %s: %s", f.Name, c.Line, msg, snippet(c.Text)))
+		}
+	}
+	return probs
+}
+
+// started reports whether the translation of f has been started.
+func started(f *gocomment.File) bool {
+	for _, c := range f.Comments {
+		if c.Lang == gocomment.Chinese {
+			return true
+		}
+	}
+	return false
+}
+
+// A count is the number of comments of a directory.
+type count struct {
+	dir                 string
+	files, started      int
+	english, translated int
+	orphans             int
+}
+
+func (c *count) percent() float64 {
+	if c.english == 0 {
+		return 100
+	}
+	return 100 * float64(c.translated) / float64(c.english)
+}
+
+type byDir []*count
+
+func (s byDir) Len() int           { return len(s) }
+func (s byDir) Less(i, j int) bool { return s[i].dir < s[j].dir }
+func (s byDir) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// printStats prints the counts of the comments of the files, by directory,
+// and their total.
+func printStats(w io.Writer, files []*gocomment.File) {
+	dirs := make(map[string]*count)
+	total := &count{dir: "total"}
+	var list []*count
+	for _, f := range files {
+		dir := filepath.Dir(f.Name)
+		c := dirs[dir]
+		if c == nil {
+			c = &count{dir: dir}
+			dirs[dir] = c
+			list = append(list, c)
+		}
+		for _, c := range []*count{c, total} {
+			c.files++
+			if started(f) {
+				c.started++
+			}
+			c.english += len(f.Translated()) + len(f.Untranslated())
+			c.translated += len(f.Translated())
+			c.orphans += len(f.Orphans())
+		}
+	}
+	sort.Sort(byDir(list))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "directory\tprograms\tstarted\tcomments\ttranslated\torphans\tcoverage\t\n")
+	for _, c := range append(list, total) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t\n", c.dir, c.files, c.started, c.english, c.translated, c.orphans, c.percent())
+	}
+	tw.Flush()
+}
+
+// write writes the files with their comments in the language lang, below
+// the directory out, or to w if out is "".
+func write(w io.Writer, files []*gocomment.File, lang, out string) error {
+	for _, f := range files {
+		src := f.Only(lang)
+		if out == "" {
+			if _, err := w.Write(src); err != nil {
+				return err
+			}
+			continue
+		}
+		dst := filepath.Join(out, f.Name)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dst, src, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/gocomment"
+)
+
+var testFiles = map[string]string{
+	"poll.go": `package main
+
+// Poll executes an HTTP HEAD request for url.
+
+// Poll 为 url 执行一个 HTTP HEAD 请求。
+func Poll(url string) {
+	// Launch some Poller goroutines.
+	go Poller()
+	// 剩余的请求。
+}
+`,
+	"hello.go": `package main
+
+// Print a greeting.
+func main() {}
+`,
+}
+
+func parse() []*gocomment.File {
+	var files []*gocomment.File
+	for _, name := range []string{"hello.go", "poll.go"} {
+		files = append(files, gocomment.Parse(name, []byte(testFiles[name])))
+	}
+	return files
+}
+
+func TestCheck(t *testing.T) {
+	got := strings.Join(check(parse(), false), "\n")
+	want := "poll.go:7: untranslated comment: Launch some Poller goroutines.\n" +
+		"poll.go:9: Chinese comment without the English original: 剩余的请求。"
+	if got != want {
+		t.Errorf("check:\n%s\nwant:\n%s", got, want)
+	}
+	if got := check(parse(), true); len(got) != 3 || got[0] != "hello.go:3: untranslated comment: Print a greeting." {
+		t.Errorf("check -all = %q", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	var buf bytes.Buffer
+	printStats(&buf, parse())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("stats:\n%s", buf.String())
+	}
+	if f := strings.Fields(lines[2]); strings.Join(f, " ") != "total 2 1 3 1 1 33.3%" {
+		t.Errorf("total = %q", f)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Doccomment checks the translation of the comments of the Go programs of
+// doc, blog, talks and tour, such as doc/zh_CN/codewalk/urlpoll.go, which
+// keep every English comment next to its Chinese translation; see package
+// gocomment for the layouts.
+//
+// By default, doccomment reports the English comments without a translation
+// and the Chinese comments without an English original, and exits with
+// status 1 if there are any. The programs without a single Chinese comment
+// have not been started, and are only reported with -all.
+//
+// With -stats, it prints instead the number of comments and of translated
+// comments of each directory. With -lang, it writes the programs with their
+// comments in one language: -lang en drops the Chinese comments, and
+// -lang zh the English comments that have a translation.
+//
+// Usage:
+//
+//	doccomment [flags] [path ...]
+//
+// The paths, files or directories, default to doc, blog, talks and tour.
+// The flags are:
+//
+//	-all
+//		also check the programs without Chinese comments
+//	-lang en|zh
+//		write the programs with their comments in one language
+//	-o dir
+//		with -lang, write the programs below dir instead of standard output
+//	-stats
+//		print the translated comments of each directory
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/gocomment"
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+var (
+	all    = flag.Bool("all", false, "also check the programs without Chinese comments")
+	lang   = flag.String("lang", "", "write the programs with their comments in `language` en or zh")
+	outDir = flag.String("o", "", "with -lang, write the programs below `dir`")
+	stats  = flag.Bool("stats", false, "print the translated comments of each directory")
+)
+
+var defaultPaths = []string{"doc", "blog", "talks", "tour"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: doccomment [flags] [path ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("doccomment: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *lang != "" && *lang != gocomment.English && *lang != gocomment.Chinese {
+		usage()
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+
+	var files []*gocomment.File
+	err := zhtext.Walk(paths, func(f *zhtext.File, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.Kind == zhtext.Go {
+			files = append(files, gocomment.Parse(f.Name, f.Src))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case *lang != "":
+		if err := write(os.Stdout, files, *lang, *outDir); err != nil {
+			log.Fatal(err)
+		}
+	case *stats:
+		printStats(os.Stdout, files)
+	default:
+		probs := check(files, *all)
+		for _, p := range probs {
+			fmt.Println(p)
+		}
+		if len(probs) > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// snippet returns the start of the first line of a comment.
+func snippet(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i] + " …"
+	}
+	if r := []rune(text); len(r) > 60 {
+		text = string(r[:60]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Doccover reports how much of the package documentation in the translations
+// tree has been translated.
+//
+// It walks the src directory, pairs the English and Chinese blocks of every
+// doc_zh_CN.go stub and counts, per package and per declaration kind, the
+// documented declarations, those with a Chinese block, those whose Chinese
+// block is flagged with a "//zh:fuzzy" marker (see docgen -merge) and those
+// that are still English only. Fuzzy translations do not count as covered.
+// Among the translated declarations, it counts apart those whose translation
+// has an up-to-date review (see docreview).
+//
+// Usage:
+//
+//	doccover [flags] [importpath ...]
+//
+// An import path restricts the report to that package; a path ending in
+// "/..." also includes the packages below it. The flags are:
+//
+//	-src dir
+//		root of the stub tree (default "src")
+//	-format text|json|html
+//		output format (default text)
+//	-go version
+//		Go version of the English texts: older reviews are outdated
+//	-sort path|coverage
+//		order of the packages (default path)
+//	-v
+//		break the text report down by declaration kind
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	srcDir  = flag.String("src", "src", "root of the stub tree")
+	format  = flag.String("format", "text", "output format: text, json or html")
+	goVer   = flag.String("go", "", "Go `version` of the English texts: older reviews are outdated")
+	sortBy  = flag.String("sort", "path", "sort packages by path or coverage")
+	verbose = flag.Bool("v", false, "break the text report down by declaration kind")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: doccover [flags] [importpath ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("doccover: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	report, err := buildReport(*srcDir, flag.Args(), *goVer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch *sortBy {
+	case "path":
+	case "coverage":
+		sort.Stable(byCoverage(report.Packages))
+	default:
+		log.Fatalf("unknown sort order %q", *sortBy)
+	}
+
+	switch *format {
+	case "text":
+		err = writeText(os.Stdout, report, *verbose)
+	case "json":
+		err = writeJSON(os.Stdout, report)
+	case "html":
+		err = writeHTML(os.Stdout, report)
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func buildReport(root string, patterns []string, goVersion string) (*Report, error) {
+	report := newReport()
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, root, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(patterns, pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			// Report what could be parsed; the stub tree must
+			// not hide the rest of the numbers.
+			log.Print(err)
+		}
+		report.add(pkg, goVersion)
+		return nil
+	})
+	return report, err
+}
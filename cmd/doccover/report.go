@@ -0,0 +1,189 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"text/tabwriter"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// A Count holds the coverage numbers of a set of translation units.
+type Count struct {
+	Total       int // documented declarations
+	Translated  int // declarations with a Chinese block
+	Fuzzy       int // declarations with a Chinese block flagged as fuzzy
+	EnglishOnly int // declarations with an English block only
+	Reviewed    int // translated declarations with an up-to-date review
+}
+
+func (c *Count) add(u *docstub.Unit, goVersion string) {
+	if !u.Documented() {
+		return
+	}
+	c.Total++
+	switch {
+	case u.Translated() && u.Fuzzy():
+		c.Fuzzy++
+	case u.Translated():
+		c.Translated++
+		if state, _ := u.ReviewState(goVersion); state == docstub.UpToDate {
+			c.Reviewed++
+		}
+	default:
+		c.EnglishOnly++
+	}
+}
+
+func (c *Count) merge(d *Count) {
+	c.Total += d.Total
+	c.Translated += d.Translated
+	c.Fuzzy += d.Fuzzy
+	c.EnglishOnly += d.EnglishOnly
+	c.Reviewed += d.Reviewed
+}
+
+// Percent returns the translated share of c in percent.
+func (c *Count) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return 100 * float64(c.Translated) / float64(c.Total)
+}
+
+// ReviewedPercent returns the reviewed share of c in percent.
+func (c *Count) ReviewedPercent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return 100 * float64(c.Reviewed) / float64(c.Total)
+}
+
+// A KindCount is the Count of one declaration kind.
+type KindCount struct {
+	Kind string
+	Count
+}
+
+// A PackageReport holds the coverage of one package.
+type PackageReport struct {
+	ImportPath string
+	Count
+	Kinds []*KindCount // indexed by docstub.Kind
+}
+
+// A Report holds the coverage of the whole stub tree.
+type Report struct {
+	Packages []*PackageReport
+	Count
+	Kinds []*KindCount // indexed by docstub.Kind
+}
+
+func newKindCounts() []*KindCount {
+	kinds := make([]*KindCount, len(docstub.Kinds))
+	for _, k := range docstub.Kinds {
+		kinds[k] = &KindCount{Kind: k.String()}
+	}
+	return kinds
+}
+
+func newReport() *Report {
+	return &Report{Kinds: newKindCounts()}
+}
+
+func (r *Report) add(pkg *docstub.Package, goVersion string) {
+	p := &PackageReport{ImportPath: pkg.ImportPath, Kinds: newKindCounts()}
+	for _, u := range pkg.Units() {
+		p.Kinds[u.Kind].add(u, goVersion)
+		p.Count.add(u, goVersion)
+	}
+	for k, c := range p.Kinds {
+		r.Kinds[k].merge(&c.Count)
+	}
+	r.Count.merge(&p.Count)
+	r.Packages = append(r.Packages, p)
+}
+
+// byCoverage sorts packages by ascending coverage, so that the packages most
+// in need of work come first.
+type byCoverage []*PackageReport
+
+func (s byCoverage) Len() int           { return len(s) }
+func (s byCoverage) Less(i, j int) bool { return s[i].Percent() < s[j].Percent() }
+func (s byCoverage) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func writeText(w io.Writer, r *Report, verbose bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "package\tkind\ttotal\tchinese\tfuzzy\tenglish only\tcoverage\treviewed\treview coverage\n")
+	line := func(path, kind string, c *Count) {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%.1f%%\t%d\t%.1f%%\n", path, kind, c.Total, c.Translated, c.Fuzzy, c.EnglishOnly, c.Percent(), c.Reviewed, c.ReviewedPercent())
+	}
+	for _, p := range r.Packages {
+		line(p.ImportPath, "", &p.Count)
+		if verbose {
+			for _, k := range p.Kinds {
+				if k.Total > 0 {
+					line("", k.Kind, &k.Count)
+				}
+			}
+		}
+	}
+	for _, k := range r.Kinds {
+		line("total", k.Kind, &k.Count)
+	}
+	line("total", "", &r.Count)
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, r *Report) error {
+	b, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = w.Write(b)
+	return err
+}
+
+func writeHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent":  func(c Count) string { return fmt.Sprintf("%.1f%%", c.Percent()) },
+	"reviewed": func(c Count) string { return fmt.Sprintf("%.1f%%", c.ReviewedPercent()) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>翻译覆盖率</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: right; }
+td.path { text-align: left; }
+tr.none td { background: #fdd; }
+tr.done td { background: #dfd; }
+</style>
+</head>
+<body>
+<h1>翻译覆盖率</h1>
+<table>
+<tr>
+<th>package</th>{{range .Kinds}}<th>{{.Kind}}</th>{{end}}<th>chinese</th><th>fuzzy</th><th>english only</th><th>total</th><th>coverage</th><th>reviewed</th><th>review coverage</th>
+</tr>
+{{range .Packages}}<tr{{if eq .Translated 0}} class="none"{{else if eq .Translated .Total}} class="done"{{end}}>
+<td class="path">{{.ImportPath}}</td>{{range .Kinds}}<td>{{if .Total}}{{.Translated}}/{{.Total}}{{end}}</td>{{end}}<td>{{.Translated}}</td><td>{{.Fuzzy}}</td><td>{{.EnglishOnly}}</td><td>{{.Total}}</td><td>{{percent .Count}}</td><td>{{.Reviewed}}</td><td>{{reviewed .Count}}</td>
+</tr>
+{{end}}<tr>
+<td class="path"><b>total</b></td>{{range .Kinds}}<td>{{.Translated}}/{{.Total}}</td>{{end}}<td>{{.Translated}}</td><td>{{.Fuzzy}}</td><td>{{.EnglishOnly}}</td><td>{{.Total}}</td><td>{{percent .Count}}</td><td>{{.Reviewed}}</td><td>{{reviewed .Count}}</td>
+</tr>
+</table>
+</body>
+</html>
+`))
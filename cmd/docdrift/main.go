@@ -0,0 +1,225 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docdrift reports where the doc stubs of the translations tree have drifted
+// from the Go sources of a GOROOT.
+//
+// For each src/<importpath>/doc_zh_CN.go stub it loads the real package with
+// go/build and go/doc, for the GOOS and GOARCH named by the stub file, and
+// reports
+//
+//   - exported identifiers added upstream and missing from the stub,
+//   - stubbed identifiers that no longer exist upstream,
+//   - declarations whose signature changed,
+//   - English comments that no longer match upstream word for word, which
+//     means that the Chinese translation may describe outdated behaviour.
+//
+// Usage:
+//
+//	docdrift [flags] [importpath ...]
+//
+// An import path restricts the check to that package; a path ending in "/..."
+// also includes the packages below it. Docdrift exits with status 1 if it
+// found any drift. The flags are:
+//
+//	-goroot dir
+//		GOROOT to compare with (default $GOROOT)
+//	-src dir
+//		root of the stub tree (default "src")
+//	-v
+//		print the stub and upstream English text of changed comments
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	goroot  = flag.String("goroot", build.Default.GOROOT, "GOROOT to compare with")
+	srcDir  = flag.String("src", "src", "root of the stub tree")
+	verbose = flag.Bool("v", false, "print the English text of changed comments")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docdrift [flags] [importpath ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+var exitCode = 0
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docdrift: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	patterns := flag.Args()
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, *srcDir, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(patterns, pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			log.Print(err)
+			exitCode = 1
+		}
+		for _, f := range pkg.Files {
+			check(fset, pkg.ImportPath, f)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(exitCode)
+}
+
+// context returns the build context used to load the upstream package of a
+// stub for goos and goarch.
+func context(goos, goarch string) *build.Context {
+	ctxt := build.Default
+	ctxt.GOROOT = *goroot
+	if goos != "" || goarch != "" {
+		ctxt.CgoEnabled = false
+	}
+	if goos != "" {
+		ctxt.GOOS = goos
+	}
+	if goarch != "" {
+		ctxt.GOARCH = goarch
+	}
+	return &ctxt
+}
+
+func check(fset *token.FileSet, importPath string, stub *docstub.File) {
+	up, err := docstub.LoadUpstream(context(stub.GOOS, stub.GOARCH), fset, importPath)
+	if err != nil {
+		report(stub.Name, "package %s not found upstream: %v", importPath, err)
+		return
+	}
+
+	stubUnits, stubNames := index(stub)
+	upUnits, upNames := index(up)
+	for _, name := range upNames.list {
+		if !stubNames.has[name] {
+			report(stub.Name, "%s.%s added upstream:\n\t%s", importPath, name, declLine(up, name))
+		}
+	}
+	for _, name := range stubNames.list {
+		if !upNames.has[name] {
+			report(stub.Name, "%s.%s no longer exists upstream", importPath, name)
+		}
+	}
+	for _, u := range stub.Units {
+		up := upUnits[u.Name]
+		if up == nil || stubUnits[u.Name] != u || up.Kind != u.Kind {
+			continue
+		}
+		pos := u.Pos.String()
+		what := importPath + "." + u.Name
+		if u.Kind == docstub.PackageClause {
+			what = importPath
+		}
+		if u.Kind != docstub.PackageClause && docstub.NormalizeSignature(u.Signature) != docstub.NormalizeSignature(up.Signature) {
+			report(pos, "%s signature changed:\n\tstub:\n%s\tupstream:\n%s", what, indent(u.Signature), indent(up.Signature))
+		}
+		if u.English != "" && !docstub.SameText(u.English, up.English) {
+			report(pos, "%s English comment changed", what)
+			if *verbose {
+				fmt.Printf("\tstub:\n%s\tupstream:\n%s", indent(u.English), indent(up.English))
+			}
+		}
+	}
+}
+
+// A nameSet is the set of identifiers declared by a file, in source order.
+type nameSet struct {
+	list []string
+	has  map[string]bool
+}
+
+func (s *nameSet) add(name string) {
+	if !s.has[name] {
+		s.has[name] = true
+		s.list = append(s.list, name)
+	}
+}
+
+// index returns the units of f that correspond to a go/doc declaration,
+// by name, and the identifiers f declares. Methods are named "T.M".
+func index(f *docstub.File) (map[string]*docstub.Unit, *nameSet) {
+	units := make(map[string]*docstub.Unit)
+	names := &nameSet{has: make(map[string]bool)}
+	for _, u := range f.Units {
+		if u.Kind == docstub.PackageClause {
+			units[""] = u
+			units[u.Name] = u
+			continue
+		}
+		switch n := u.Node.(type) {
+		case *ast.ValueSpec:
+			// Documented spec of a const or var group; the
+			// group itself is the go/doc declaration.
+			continue
+		case *ast.GenDecl:
+			if n.Tok == token.TYPE && n.Lparen.IsValid() && len(n.Specs) != 1 {
+				continue // doc of a type group
+			}
+		}
+		if units[u.Name] == nil {
+			units[u.Name] = u
+		}
+		if u.Kind == docstub.Method {
+			names.add(u.Name)
+			continue
+		}
+		for _, name := range u.Names {
+			names.add(name)
+		}
+	}
+	return units, names
+}
+
+// declLine returns a one line description of the upstream declaration of
+// name.
+func declLine(f *docstub.File, name string) string {
+	for _, u := range f.Units {
+		switch {
+		case u.Name == name && (u.Kind == docstub.Func || u.Kind == docstub.Method):
+			return u.Signature
+		case u.Kind != docstub.Method && contains(u.Names, name):
+			// Show the name rather than the first line of a group.
+			return u.Kind.String() + " " + name
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func indent(s string) string {
+	return "\t\t" + strings.Replace(strings.TrimSuffix(s, "\n"), "\n", "\n\t\t", -1) + "\n"
+}
+
+func report(pos string, format string, args ...interface{}) {
+	fmt.Printf("%s: %s\n", pos, fmt.Sprintf(format, args...))
+	exitCode = 1
+}
@@ -0,0 +1,173 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docfmt checks and fixes the typography of the Chinese translations.
+//
+// It formats the Chinese blocks of the doc_zh_CN.go stubs, the translated
+// parts of the HTML documents and the Chinese comments of Go programs with
+// package zhfmt: full-width punctuation in Chinese sentences, a space
+// between Chinese characters and ASCII letters or digits, curly quotes, and,
+// in the stubs only, paragraphs wrapped to a width that counts the CJK
+// characters as two columns. The English text, the markup and the code are
+// left alone.
+//
+// By default, docfmt reports the Chinese blocks that are not formatted and
+// exits with status 1 if there are any. With -w, it rewrites the files
+// instead, like gofmt -w, and prints their names.
+//
+// Usage:
+//
+//	docfmt [flags] [path ...]
+//
+// The paths, files or directories, default to src, doc, blog, talks and tour.
+// The flags are:
+//
+//	-d
+//		print the lines of the blocks before and after formatting
+//	-w
+//		rewrite the files
+//	-width n
+//		width of the stub comments, in columns (default 80)
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/zhfmt"
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+var (
+	diff  = flag.Bool("d", false, "print the blocks before and after formatting")
+	write = flag.Bool("w", false, "rewrite the files")
+	width = flag.Int("width", docstub.LineWidth, "width of the stub comments, in `columns`")
+)
+
+var defaultPaths = []string{"src", "doc", "blog", "talks", "tour"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docfmt [flags] [path ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docfmt: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+
+	found := false
+	err := zhtext.Walk(paths, func(f *zhtext.File, err error) error {
+		if err != nil {
+			return err
+		}
+		src, changes, err := format(f)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+		if *write {
+			if err := ioutil.WriteFile(f.Name, src, 0644); err != nil {
+				return err
+			}
+			fmt.Println(f.Name)
+			return nil
+		}
+		found = true
+		for _, c := range changes {
+			fmt.Printf("%s:%d: Chinese text not formatted\n", f.Name, c.line)
+			if *diff {
+				printLines("-", c.old)
+				printLines("+", c.new)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if found {
+		os.Exit(1)
+	}
+}
+
+// A change is a block of Chinese text that formatting changes.
+type change struct {
+	line     int
+	old, new string
+}
+
+// format returns the formatted source of f and the blocks that changed.
+func format(f *zhtext.File) ([]byte, []change, error) {
+	if f.Kind == zhtext.Stub {
+		return formatStub(f)
+	}
+	var (
+		buf     bytes.Buffer
+		changes []change
+		last    int
+	)
+	for _, r := range f.Regions {
+		old := f.Text(r)
+		var new string
+		if f.Kind == zhtext.HTML {
+			new = zhfmt.HTML(old)
+		} else {
+			new = zhfmt.Text(old)
+		}
+		buf.Write(f.Src[last:r.Start])
+		buf.WriteString(new)
+		last = r.End
+		if new != old {
+			line, _ := f.Position(r.Start)
+			changes = append(changes, change{line, old, new})
+		}
+	}
+	buf.Write(f.Src[last:])
+	return buf.Bytes(), changes, nil
+}
+
+// formatStub formats the Chinese blocks of a stub, which also get their
+// paragraphs wrapped.
+func formatStub(f *zhtext.File) ([]byte, []change, error) {
+	fset := token.NewFileSet()
+	stub, err := docstub.ParseFile(fset, f.Name, f.Src)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		edits   []docstub.Edit
+		changes []change
+	)
+	for _, u := range stub.Units {
+		if !docstub.HasHan(u.Chinese) {
+			continue
+		}
+		if text := zhfmt.Doc(u.Chinese, *width); text != u.Chinese {
+			edits = append(edits, u.Edit(text, u.Fuzzy()))
+			line := fset.Position(u.ChineseDoc.Pos()).Line
+			changes = append(changes, change{line, docstub.TextComment(u.Chinese), docstub.TextComment(text)})
+		}
+	}
+	return stub.Apply(edits), changes, nil
+}
+
+func printLines(prefix, text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		fmt.Printf("%s%s\n", prefix, line)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docgen generates the skeleton doc_zh_CN.go stub of a package that has not
+// been translated yet.
+//
+// It loads the package from a GOROOT and writes a stub in the house style:
+// the copyright header, an "ignore" build constraint, the package clause and
+// every exported const, var, type, function and method, each with its English
+// comment followed by an empty slot for the Chinese text, a comment holding
+// only the "//zh:untranslated" marker.
+//
+// Usage:
+//
+//	docgen [flags] importpath ...
+//
+// By default the stub is written to standard output. The flags are:
+//
+//	-goroot dir
+//		GOROOT to load the package from (default $GOROOT)
+//	-goos os, -goarch arch
+//		generate the platform specific stub doc_zh_CN_$GOOS.go,
+//		doc_zh_CN_$GOARCH.go or doc_zh_CN_$GOOS_$GOARCH.go
+//	-src dir
+//		root of the stub tree (default "src")
+//	-w
+//		write the stub to src/<importpath>/ instead of standard output
+//	-f
+//		with -w, overwrite existing stubs
+//	-merge
+//		regenerate an existing stub, keeping its translations
+//
+// Internal packages such as cmd/pprof/internal/report are loaded like any
+// other package.
+//
+// With -merge, docgen updates an existing stub to the current upstream
+// English, the way gettext's msgmerge updates a PO file: every Chinese
+// comment whose declaration still exists is carried over, and flagged with
+// a "//zh:fuzzy" marker if the English text it translates has changed.
+// Translations of declarations that no longer exist are listed on standard
+// error. Once a fuzzy translation has been reviewed, delete its marker line,
+// or record the review with docreview -mark, which replaces it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/build"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	goroot = flag.String("goroot", build.Default.GOROOT, "GOROOT to load packages from")
+	goos   = flag.String("goos", "", "generate the stub for this GOOS")
+	goarch = flag.String("goarch", "", "generate the stub for this GOARCH")
+	srcDir = flag.String("src", "src", "root of the stub tree")
+	write  = flag.Bool("w", false, "write stubs into the stub tree")
+	force  = flag.Bool("f", false, "with -w, overwrite existing stubs")
+	merge  = flag.Bool("merge", false, "regenerate an existing stub, keeping its translations")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docgen [flags] importpath ...\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+
+	exitCode := 0
+	for _, path := range flag.Args() {
+		if err := generate(path); err != nil {
+			log.Print(err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// context returns the build context used to load the upstream package for
+// goos and goarch.
+func context(goos, goarch string) *build.Context {
+	ctxt := build.Default
+	ctxt.GOROOT = *goroot
+	if goos != "" || goarch != "" {
+		ctxt.CgoEnabled = false
+	}
+	if goos != "" {
+		ctxt.GOOS = goos
+	}
+	if goarch != "" {
+		ctxt.GOARCH = goarch
+	}
+	return &ctxt
+}
+
+func generate(importPath string) error {
+	fset := token.NewFileSet()
+	f, err := docstub.LoadUpstream(context(*goos, *goarch), fset, importPath)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(*srcDir, filepath.FromSlash(importPath))
+	filename := filepath.Join(dir, docstub.StubName(*goos, *goarch))
+	if *merge {
+		old, err := docstub.ParseFile(fset, filename, nil)
+		if err != nil {
+			return err
+		}
+		stats := docstub.Merge(f, old)
+		for _, u := range stats.Obsolete {
+			log.Printf("%s: %s: translation dropped", u.Pos, u.Name)
+		}
+		log.Printf("%s: %d kept, %d fuzzy, %d untranslated, %d dropped",
+			filename, stats.Kept, stats.Fuzzy, stats.New, len(stats.Obsolete))
+	}
+	src, err := docstub.Format(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", importPath, err)
+	}
+	if !*write {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	if _, err := os.Stat(filename); err == nil && !*force && !*merge {
+		return fmt.Errorf("%s already exists; use -f to overwrite it", filename)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, src, 0644)
+}
@@ -0,0 +1,338 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// A problem is a difference between the code in the English and in the
+// Chinese text of a unit.
+type problem struct {
+	unit *docstub.Unit
+	msg  string
+}
+
+var (
+	// identRx matches identifiers, possibly qualified (io.Reader,
+	// b.Len), and calls whose arguments hold no parentheses (f(c),
+	// len(p), Copy(dst, src)).
+	identRx = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*(\([^()\n]*\))?`)
+	urlRx   = regexp.MustCompile(`[a-z]+://\S+|www\.\S+`)
+
+	// acronymsRx matches plural acronyms, such as APIs.
+	acronymsRx = regexp.MustCompile(`^[A-Z]+s$`)
+
+	// proseRx matches four words in a row, which code rarely has.
+	proseRx = regexp.MustCompile(`[A-Za-z]+( [A-Za-z]+){3}`)
+)
+
+// notCode lists words that look like code but are English.
+var notCode = map[string]bool{
+	"e.g": true, "i.e": true, "etc": true,
+}
+
+// stopWords are English words not taken for parameter names.
+var stopWords = map[string]bool{
+	"a": true, "A": true, "I": true, "an": true, "and": true, "as": true,
+	"at": true, "be": true, "by": true, "do": true, "for": true, "if": true,
+	"in": true, "is": true, "it": true, "no": true, "not": true, "of": true,
+	"on": true, "or": true, "so": true, "the": true, "to": true, "up": true,
+}
+
+// checker checks the units of a package.
+type checker struct {
+	names map[string]bool // names declared by the package
+}
+
+func newChecker(pkg *docstub.Package) *checker {
+	c := &checker{names: make(map[string]bool)}
+	for _, u := range pkg.Units() {
+		if u.Kind == docstub.PackageClause {
+			continue
+		}
+		for _, name := range u.Names {
+			c.names[name] = true
+		}
+		if u.Kind == docstub.Method {
+			c.names[u.Name] = true // "T.M"
+		}
+	}
+	return c
+}
+
+// check returns the problems of a translated unit.
+func (c *checker) check(u *docstub.Unit) []problem {
+	if u.English == "" || !u.Translated() {
+		return nil
+	}
+	var probs []problem
+	report := func(format string, args ...interface{}) {
+		probs = append(probs, problem{u, fmt.Sprintf(format, args...)})
+	}
+
+	// The code blocks must be kept; comments in them may be translated,
+	// and so may blocks of prose, such as tables.
+	zhBlocks := make(map[string]bool)
+	for _, b := range codeBlocks(u.Chinese) {
+		zhBlocks[stripComments(b)] = true
+	}
+	for _, b := range codeBlocks(u.English) {
+		if b = stripComments(b); !zhBlocks[b] && !proseRx.MatchString(b) {
+			report("code block changed or missing:\n%s", indent(b))
+		}
+	}
+
+	params := declNames(u.Node)
+	self := make(map[string]bool)
+	for _, name := range u.Names {
+		self[name] = true
+	}
+	zh := tokens(u.Chinese)
+	zhFlat := strings.Join(strings.Fields(u.Chinese), "")
+	seen := make(map[string]bool)
+	for _, t := range tokens(u.English) {
+		if seen[t] || !c.isCode(t, params) {
+			continue
+		}
+		seen[t] = true
+		if u.Kind == docstub.PackageClause && t == "Package" {
+			continue
+		}
+		// The name of the declaration itself is usually the subject of the
+		// English sentence, and left out in Chinese.
+		if self[t] {
+			continue
+		}
+		if containsWord(u.Chinese, t) || strings.Contains(t, "(") && strings.Contains(zhFlat, strings.Join(strings.Fields(t), "")) {
+			continue
+		}
+		switch alt := c.alternative(t, zh); {
+		case alt == "":
+			report("%s missing", t)
+		case qualifier(t) != "" && base(alt) == base(t) && qualifier(alt) != "":
+			report("%s written with the wrong package qualifier as %s", t, alt)
+		case qualifier(t) != "" && alt == base(t):
+			report("%s written without its package qualifier", t)
+		case base(alt) == base(t):
+			report("%s written as %s", t, alt)
+		default:
+			report("%s misspelled as %s", t, alt)
+		}
+	}
+	return probs
+}
+
+// isCode reports whether the token of an English text is Go code: a call,
+// a qualified identifier, a name declared in the package or by the
+// declaration, or a word that can only be an identifier, like FieldsFunc,
+// utf8 or O_RDONLY.
+func (c *checker) isCode(t string, params map[string]bool) bool {
+	switch {
+	case notCode[t]:
+		return false
+	case strings.Contains(t, "("), strings.Contains(t, "."):
+		return true
+	case c.names[t], params[t] && !stopWords[t]:
+		return true
+	case acronymsRx.MatchString(t):
+		return false
+	}
+	upper, lower, other := false, false, false
+	for i, r := range t {
+		switch {
+		case unicode.IsUpper(r):
+			upper = upper || i > 0
+		case unicode.IsLower(r):
+			lower = true
+		default:
+			other = true
+		}
+	}
+	// Only a lower case word can take digits: Base64 is a word, utf8 is not.
+	return lower && (upper || other && !unicode.IsUpper(rune(t[0])))
+}
+
+// alternative returns the token of the Chinese text that t was most likely
+// turned into, or "". Names declared in the package are correct names of
+// their own, not alternatives.
+func (c *checker) alternative(t string, zh []string) string {
+	best, bestDist := "", 0
+	for _, z := range zh {
+		switch {
+		case c.names[z] && z != base(t):
+			continue
+		case base(z) == base(t):
+			return z
+		case len(t) >= 4:
+			d := distance(strings.ToLower(t), strings.ToLower(z))
+			if d <= len(t)/4 && (best == "" || d < bestDist) {
+				best, bestDist = z, d
+			}
+		}
+	}
+	return best
+}
+
+// tokens returns the identifiers and calls in the text, without the URLs.
+func tokens(text string) []string {
+	return identRx.FindAllString(urlRx.ReplaceAllString(text, " "), -1)
+}
+
+// containsWord reports whether the text holds the token as a whole, not as
+// part of a longer identifier.
+func containsWord(text, t string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], t)
+		if j < 0 {
+			return false
+		}
+		i += j
+		end := i + len(t)
+		// A period after the token may end the sentence.
+		after := end == len(text) || !isIdentByte(text[end]) ||
+			text[end] == '.' && (end+1 == len(text) || !isIdentByte(text[end+1]))
+		if (i == 0 || !isIdentByte(text[i-1])) && after {
+			return true
+		}
+		i++
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '.'
+}
+
+// qualifier returns the package or receiver of a qualified identifier.
+func qualifier(t string) string {
+	t = strings.SplitN(t, "(", 2)[0]
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		return t[:i]
+	}
+	return ""
+}
+
+// base returns the identifier without its qualifier and arguments.
+func base(t string) string {
+	t = strings.SplitN(t, "(", 2)[0]
+	return t[strings.LastIndex(t, ".")+1:]
+}
+
+// declNames returns the names declared by a declaration, such as the
+// parameters and results of a function or the fields of a struct.
+func declNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			for _, id := range n.Names {
+				names[id.Name] = true
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				names[id.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// codeBlocks returns the indented blocks of doc text, unindented.
+func codeBlocks(text string) []string {
+	var blocks []string
+	var cur []string
+	flush := func() {
+		if len(cur) > 0 {
+			blocks = append(blocks, unindent(cur))
+			cur = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, " "), strings.HasPrefix(line, "\t"):
+			cur = append(cur, line)
+		case line == "" && len(cur) > 0:
+			cur = append(cur, line)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return blocks
+}
+
+func unindent(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	prefix := ""
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ws := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if i == 0 || len(ws) < len(prefix) {
+			prefix = ws
+		}
+	}
+	var out []string
+	for _, line := range lines {
+		out = append(out, strings.TrimRight(strings.TrimPrefix(line, prefix), " \t"))
+	}
+	return strings.Join(out, "\n")
+}
+
+// stripComments removes the // comments of a code block, which translators
+// may translate, and collapses the white space between the words of its
+// lines, which only aligns them.
+func stripComments(block string) string {
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		if j := strings.Index(line, "//"); j >= 0 && !strings.Contains(line[:j], "://") {
+			line = line[:j]
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		lines[i] = indent + strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func indent(s string) string {
+	return "\t" + strings.Replace(s, "\n", "\n\t", -1)
+}
+
+// distance returns the Levenshtein distance of a and b, in bytes.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			d := prev[j-1]
+			if a[i-1] != b[j-1] {
+				d++
+			}
+			if x := prev[j] + 1; x < d {
+				d = x
+			}
+			if x := cur[j-1] + 1; x < d {
+				d = x
+			}
+			cur[j] = d
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
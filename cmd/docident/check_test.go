@@ -0,0 +1,95 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+const testStub = `// +build ignore
+
+// Package p is a test.
+
+// p 包用于测试。
+package p
+
+// FieldsFunc splits s at each run of code points c satisfying f(c), as
+// unicode.IsSpace does for Fields.
+
+// 将字符串按满足 f 的码值分割，就像 utf8.IsSpace 对 Fields 那样。
+func FieldsFunc(s string, f func(rune) bool) []string
+
+// Fields splits s around white space. See FieldsFunc and io.Reader.
+
+// Fields 在空白处分割s，见 FeildsFunc 和 Reader。
+func Fields(s string) []string
+
+// Sqrt returns the square root of x.
+//
+// Special cases are:
+//
+//	Sqrt(+Inf) = +Inf
+//	Sqrt(NaN) = NaN
+
+// 返回 x 的平方根。
+//
+// 特殊情况：
+//
+//	Sqrt(+Inf)  = +Inf
+//	Sqrt(NaN) = 非数
+func Sqrt(x float64) float64
+
+// Copy reads with T.ReadAt.
+
+// Copy 用 T.Read 读取。
+func Copy()
+
+type T struct{}
+
+func (t *T) Read(p []byte) (int, error)
+
+func (t *T) ReadAt(p []byte, off int64) (int, error)
+`
+
+func TestCheck(t *testing.T) {
+	f, err := docstub.ParseFile(token.NewFileSet(), "doc_zh_CN.go", []byte(testStub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newChecker(&docstub.Package{ImportPath: "p", Files: []*docstub.File{f}})
+	for _, tt := range []struct {
+		name string
+		want []string
+	}{
+		{"FieldsFunc", []string{
+			"s missing",
+			"f(c) written as f",
+			"unicode.IsSpace written with the wrong package qualifier as utf8.IsSpace",
+		}},
+		{"Fields", []string{
+			"FieldsFunc misspelled as FeildsFunc",
+			"io.Reader written without its package qualifier",
+		}},
+		{"Sqrt", []string{
+			"code block changed or missing:\n\tSqrt(+Inf) = +Inf\n\tSqrt(NaN) = NaN",
+		}},
+		// T.Read is another method, not a misspelling of T.ReadAt.
+		{"Copy", []string{
+			"T.ReadAt missing",
+		}},
+	} {
+		var got []string
+		for _, p := range c.check(f.Lookup(tt.name)) {
+			got = append(got, p.msg)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
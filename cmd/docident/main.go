@@ -0,0 +1,78 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docident checks that the Chinese doc comments of the stubs keep the Go code
+// of their English originals, which godoc needs to link identifiers and to
+// present examples.
+//
+// For every translated declaration, the identifiers and calls of the English
+// comment must appear unchanged in the Chinese one: qualified identifiers
+// such as unicode.IsSpace, calls such as f(c), the names declared by the
+// package and the parameter, result and field names of the declaration, and
+// words that can only be identifiers, such as FieldsFunc or utf8. The name of
+// the declaration itself may be left out. Docident reports the missing ones,
+// telling misspelled identifiers and wrong package qualifiers apart, and the
+// indented code blocks that were not kept as they are; comments in code blocks
+// may be translated. It exits with status 1 if there are problems.
+//
+// Usage:
+//
+//	docident [flags] [importpath ...]
+//
+// The flags are:
+//
+//	-src dir
+//		root of the stub tree (default "src")
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"log"
+	"os"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var srcDir = flag.String("src", "src", "root of the stub tree")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docident [flags] [importpath ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docident: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	found := false
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, *srcDir, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(flag.Args(), pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c := newChecker(pkg)
+		ids := pkg.IDs()
+		for i, u := range pkg.Units() {
+			for _, p := range c.check(u) {
+				found = true
+				fmt.Printf("%s: %s: %s\n", u.Pos, ids[i], p.msg)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if found {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docoverlay writes a copy of $GOROOT/src whose doc comments are in Chinese.
+//
+// It copies every file of a pristine GOROOT's src directory, and in the
+// packages that have stubs, replaces the doc comment of each translated
+// declaration by its Chinese text from the doc_zh_CN.go stubs, or adds the
+// Chinese text after the English one. Only comments change: the code of
+// every file is checked to be the same, token for token, as the original.
+// The copy can stand in for $GOROOT/src for any tool that reads doc
+// comments: go doc, godoc, gopls.
+//
+// Usage:
+//
+//	docoverlay [flags] -o dir
+//
+// The flags are:
+//
+//	-goroot dir
+//		pristine GOROOT to copy src from (default $GOROOT)
+//	-src dir
+//		root of the stub tree (default "src")
+//	-o dir
+//		output directory, which must not exist
+//	-mode mode
+//		"replace" to replace the English comments by the Chinese ones,
+//		"append" to keep the English text and add the Chinese text after
+//		it (default "replace")
+//
+// Directives such as //go:noinline in a doc comment are kept, after the
+// new text. A declaration that has no doc comment upstream but a
+// translation in the stub gets one. The package doc goes to doc.go, or to
+// the first file that has a package doc.
+//
+// The generic stub wins over the platform specific stubs: those only add
+// the declarations the generic stub lacks, and their text is used for the
+// files of every platform.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	goroot = flag.String("goroot", build.Default.GOROOT, "pristine GOROOT to copy src from")
+	srcDir = flag.String("src", "src", "root of the stub tree")
+	outDir = flag.String("o", "", "output `directory`, which must not exist")
+	mode   = flag.String("mode", modeReplace, "replace or append the English comments")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docoverlay [flags] -o dir\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docoverlay: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 || *outDir == "" || (*mode != modeReplace && *mode != modeAppend) {
+		usage()
+	}
+	if _, err := os.Lstat(*outDir); err == nil {
+		log.Fatalf("%s already exists", *outDir)
+	}
+
+	o := &overlay{stubs: *srcDir, mode: *mode}
+	if err := o.write(filepath.Join(*goroot, "src"), *outDir); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d comments translated in %d files of %d packages\n", o.comments, o.files, o.packages)
+	if len(o.errors) > 0 {
+		for _, err := range o.errors {
+			log.Print(err)
+		}
+		os.Exit(1)
+	}
+}
+
+// An overlay writes the copy of a src directory.
+type overlay struct {
+	stubs string // root of the stub tree
+	mode  string
+
+	packages, files, comments int
+	errors                    []error // files copied unchanged
+}
+
+// write copies the tree src to dst, translating the packages that have
+// stubs.
+func (o *overlay) write(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			if err := os.MkdirAll(target, info.Mode().Perm()|0700); err != nil {
+				return err
+			}
+			return o.writeDir(path, target, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+}
+
+// writeDir copies the regular files of the directory src, of the package
+// importPath, to dst.
+func (o *overlay) writeDir(src, dst, importPath string) error {
+	infos, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	var t translations
+	var pkgName string
+	if !strings.Contains("/"+importPath+"/", "/testdata/") {
+		stubs, err := docstub.ParseDir(token.NewFileSet(), filepath.Join(o.stubs, filepath.FromSlash(importPath)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(stubs) > 0 {
+			t = loadTranslations(stubs)
+			pkgName = stubs[0].Package
+		}
+	}
+	docFile := ""
+	if t != nil {
+		o.packages++
+		docFile = packageDocFile(src, infos, pkgName)
+	}
+
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		name := info.Name()
+		srcFile := filepath.Join(src, name)
+		if t == nil || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			if err := copyFile(srcFile, filepath.Join(dst, name), info.Mode()); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := ioutil.ReadFile(srcFile)
+		if err != nil {
+			return err
+		}
+		out, n, err := rewrite(srcFile, data, t, o.mode, name == docFile)
+		if err != nil {
+			o.errors = append(o.errors, err)
+			out, n = data, 0
+		}
+		if n > 0 {
+			o.files++
+			o.comments += n
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, name), out, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageDocFile returns the name of the file of package pkgName in dir
+// that gets the package doc: doc.go, or the first file with a package doc,
+// or the first file of the package.
+func packageDocFile(dir string, infos []os.FileInfo, pkgName string) string {
+	var names, documented []string
+	for _, info := range infos {
+		name := info.Name()
+		if !info.Mode().IsRegular() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil || f.Name.Name != pkgName {
+			continue
+		}
+		if name == "doc.go" {
+			return name
+		}
+		names = append(names, name)
+		if f.Doc != nil {
+			documented = append(documented, name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(documented)
+	switch {
+	case len(documented) > 0:
+		return documented[0]
+	case len(names) > 0:
+		return names[0]
+	}
+	return ""
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,235 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// translations maps the declarations of a package to their Chinese doc
+// text, as given by key.
+type translations map[string]string
+
+// key returns the key of a declaration: "T.M" for a method, "(" followed by
+// the first name for the doc of a const or var group, and the first name
+// otherwise, for a spec or an ungrouped declaration.
+func key(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Recv != nil && len(n.Recv.List) > 0 {
+			return recvName(n.Recv.List[0].Type) + "." + n.Name.Name
+		}
+		return n.Name.Name
+	case *ast.GenDecl:
+		if len(n.Specs) == 0 {
+			return ""
+		}
+		if n.Lparen.IsValid() {
+			return "(" + key(n.Specs[0])
+		}
+		return key(n.Specs[0])
+	case *ast.ValueSpec:
+		return n.Names[0].Name
+	case *ast.TypeSpec:
+		return n.Name.Name
+	}
+	return ""
+}
+
+// recvName returns the base type name of a receiver: T for T, *T, T[K] and
+// *T[K]. Unlike docstub.RecvTypeName, it handles the generic types of
+// recent releases, which the stubs do not have yet.
+func recvName(x ast.Expr) string {
+	name := ""
+	ast.Inspect(x, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && name == "" {
+			name = id.Name
+		}
+		return name == ""
+	})
+	return name
+}
+
+// packageKey is the key of the package doc.
+const packageKey = "package"
+
+// loadTranslations returns the translated units of the stubs, the generic
+// stub first: a platform specific stub only adds the declarations the
+// generic one lacks.
+func loadTranslations(stubs []*docstub.File) translations {
+	t := make(translations)
+	for _, f := range stubs {
+		for _, u := range f.Units {
+			if !u.Translated() {
+				continue
+			}
+			k := u.Name
+			switch n := u.Node.(type) {
+			case *ast.File:
+				k = packageKey
+			case *ast.GenDecl:
+				if n.Lparen.IsValid() {
+					k = "(" + k
+				}
+			}
+			if _, ok := t[k]; !ok {
+				t[k] = u.Chinese
+			}
+		}
+	}
+	return t
+}
+
+// Modes of the overlay.
+const (
+	modeReplace = "replace" // the Chinese text replaces the English one
+	modeAppend  = "append"  // the Chinese text follows the English one
+)
+
+// directiveRx matches the comment lines that are not doc text but
+// directives for the tools, which are kept: //go:noinline, //line,
+// //export and the like.
+var directiveRx = regexp.MustCompile(`^//([a-z0-9]+:[a-z0-9]|line |export |extern |sys(nb)? )`)
+
+// An edit replaces src[start:end] by text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+type byStart []edit
+
+func (s byStart) Len() int           { return len(s) }
+func (s byStart) Less(i, j int) bool { return s[i].start < s[j].start }
+func (s byStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// rewrite returns the source file src with the doc comments of its
+// translated declarations replaced by, or followed by, their Chinese text,
+// and the number of comments translated. The package doc is translated only
+// if pkgDoc is set. Only comments change: rewrite fails if the code of the
+// result differs from that of src.
+func rewrite(filename string, src []byte, t translations, mode string, pkgDoc bool) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, 0, err
+	}
+	file := fset.File(f.Pos())
+	offset := func(p token.Pos) int { return file.Offset(p) }
+	indent := func(p token.Pos) string {
+		start := offset(p) - (fset.Position(p).Column - 1)
+		return string(src[start:offset(p)])
+	}
+
+	var edits []edit
+	doc := func(k string, doc *ast.CommentGroup, pos token.Pos) {
+		zh, ok := t[k]
+		if !ok || k == "" {
+			return
+		}
+		if doc == nil {
+			// Insert the comment above the declaration.
+			in := indent(pos)
+			edits = append(edits, edit{offset(pos), offset(pos), commentLines(zh, nil, nil, in) + "\n" + in})
+			return
+		}
+		var english, directives []string
+		for _, c := range doc.List {
+			if directiveRx.MatchString(c.Text) {
+				directives = append(directives, c.Text)
+			} else if mode == modeAppend {
+				english = append(english, c.Text)
+			}
+		}
+		edits = append(edits, edit{offset(doc.Pos()), offset(doc.End()), commentLines(zh, english, directives, indent(doc.Pos()))})
+	}
+
+	if pkgDoc {
+		doc(packageKey, f.Doc, f.Package)
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			doc(key(d), d.Doc, d.Pos())
+		case *ast.GenDecl:
+			if d.Lparen.IsValid() {
+				if d.Doc != nil {
+					doc(key(d), d.Doc, d.Pos())
+				}
+				for _, s := range d.Specs {
+					var sdoc *ast.CommentGroup
+					switch s := s.(type) {
+					case *ast.ValueSpec:
+						sdoc = s.Doc
+					case *ast.TypeSpec:
+						sdoc = s.Doc
+					}
+					if sdoc != nil || d.Tok == token.TYPE {
+						doc(key(s), sdoc, s.Pos())
+					}
+				}
+				continue
+			}
+			doc(key(d), d.Doc, d.Pos())
+		}
+	}
+
+	sort.Sort(byStart(edits))
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(src[last:])
+	out := buf.Bytes()
+	if !sameCode(src, out) {
+		return nil, 0, fmt.Errorf("%s: rewriting the comments changed the code", filename)
+	}
+	return out, len(edits), nil
+}
+
+// commentLines returns the // comment holding the English comment lines,
+// the Chinese doc text and the directives, in that order, with the lines
+// after the first indented by indent.
+func commentLines(zh string, english, directives []string, indent string) string {
+	var lines []string
+	if len(english) > 0 {
+		lines = append(english, "//")
+	}
+	lines = append(lines, strings.Split(strings.TrimSuffix(docstub.TextComment(zh), "\n"), "\n")...)
+	lines = append(lines, directives...)
+	return strings.Join(lines, "\n"+indent)
+}
+
+// sameCode reports whether a and b hold the same Go tokens, ignoring the
+// comments.
+func sameCode(a, b []byte) bool {
+	var sa, sb scanner.Scanner
+	fset := token.NewFileSet()
+	sa.Init(fset.AddFile("", -1, len(a)), a, nil, 0)
+	sb.Init(fset.AddFile("", -1, len(b)), b, nil, 0)
+	for {
+		_, ta, la := sa.Scan()
+		_, tb, lb := sb.Scan()
+		if ta != tb || la != lb {
+			return false
+		}
+		if ta == token.EOF {
+			return true
+		}
+	}
+}
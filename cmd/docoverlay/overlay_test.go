@@ -0,0 +1,198 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSrc = `// Copyright 2015 The Go Authors. All rights reserved.
+
+// Package p is a test.
+package p
+
+// Size is the size.
+const Size = 8
+
+// Flags.
+const (
+	A = 1 << iota // flag A
+	B
+)
+
+// T is a type.
+type T struct {
+	x int
+}
+
+// M is a method.
+//
+//go:noinline
+func (t *T) M() int { return t.x }
+
+func F() int { return Size }
+
+// g is not exported.
+func g() {}
+`
+
+const testStub = `// +build ignore
+
+// Package p is a test.
+
+// p 包用于测试。
+package p
+
+// Size is the size.
+
+// Size 是大小。
+const Size = 8
+
+// Flags.
+
+// 标志。
+const (
+	A = 1 << iota // flag A
+	B
+)
+
+// T is a type.
+type T struct {
+}
+
+// M is a method.
+
+// M 是一个方法。
+func (t *T) M() int
+
+// F 返回大小。
+func F() int
+`
+
+func TestOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docoverlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string]string{
+		"goroot/src/p/p.go":           testSrc,
+		"goroot/src/p/p_test.go":      "package p\n\n// T is a test.\nfunc T() {}\n",
+		"goroot/src/p/README":         "p\n",
+		"stubs/p/doc_zh_CN.go":        testStub,
+		"goroot/src/q/q.go":           "// Package q is not translated.\npackage q\n",
+		"goroot/src/p/testdata/x.txt": "x\n",
+	} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, mode := range []string{modeReplace, modeAppend} {
+		out := filepath.Join(dir, "out-"+mode)
+		o := &overlay{stubs: filepath.Join(dir, "stubs"), mode: mode}
+		if err := o.write(filepath.Join(dir, "goroot", "src"), out); err != nil {
+			t.Fatal(err)
+		}
+		if len(o.errors) > 0 {
+			t.Fatal(o.errors)
+		}
+		if o.packages != 1 || o.files != 1 || o.comments != 5 {
+			t.Errorf("%s: %d packages, %d files, %d comments, want 1, 1, 5", mode, o.packages, o.files, o.comments)
+		}
+		for _, name := range []string{"p/p_test.go", "p/README", "p/testdata/x.txt", "q/q.go"} {
+			a, _ := ioutil.ReadFile(filepath.Join(dir, "goroot", "src", name))
+			b, err := ioutil.ReadFile(filepath.Join(out, name))
+			if err != nil || !bytes.Equal(a, b) {
+				t.Errorf("%s: %s not copied unchanged", mode, name)
+			}
+		}
+
+		// The overlay compiles.
+		src, err := ioutil.ReadFile(filepath.Join(out, "p", "p.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", mode, err, src)
+		}
+		if _, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil); err != nil {
+			t.Errorf("%s: %v\n%s", mode, err, src)
+		}
+		if !sameCode([]byte(testSrc), src) {
+			t.Errorf("%s: code changed:\n%s", mode, src)
+		}
+		if !strings.Contains(string(src), "// M 是一个方法。\n//go:noinline\nfunc") {
+			t.Errorf("%s: directive not kept after the comment:\n%s", mode, src)
+		}
+
+		// go/doc sees the Chinese text.
+		pkg := doc.New(&ast.Package{Name: "p", Files: map[string]*ast.File{"p.go": f}}, "p", 0)
+		docs := map[string]string{"package": pkg.Doc}
+		for _, c := range pkg.Consts {
+			docs[c.Names[0]] = c.Doc
+		}
+		for _, ty := range pkg.Types {
+			docs[ty.Name] = ty.Doc
+			for _, m := range ty.Methods {
+				docs[ty.Name+"."+m.Name] = m.Doc
+			}
+		}
+		for _, fn := range pkg.Funcs {
+			docs[fn.Name] = fn.Doc
+		}
+		for _, tt := range []struct {
+			name, zh, en string
+		}{
+			{"package", "p 包用于测试。\n", "Package p is a test.\n"},
+			{"Size", "Size 是大小。\n", "Size is the size.\n"},
+			{"A", "标志。\n", "Flags.\n"},
+			{"T", "", "T is a type.\n"},
+			{"T.M", "M 是一个方法。\n", "M is a method.\n"},
+			{"F", "F 返回大小。\n", ""},
+		} {
+			want := tt.zh
+			switch {
+			case want == "":
+				want = tt.en
+			case mode == modeAppend && tt.en != "":
+				want = tt.en + "\n" + tt.zh
+			}
+			if got := docs[tt.name]; got != want {
+				t.Errorf("%s: doc of %s = %q, want %q", mode, tt.name, got, want)
+			}
+		}
+	}
+}
+
+func TestRewriteKeepsCode(t *testing.T) {
+	// A translation for a declaration inside a function is not applied,
+	// and an indented group spec gets an indented comment.
+	src := "package p\n\nvar (\n\t// X is x.\n\tX = 1\n\tY = 2\n)\n\nfunc f() {\n\t// X is local.\n\tvar X int\n\t_ = X\n}\n"
+	out, n, err := rewrite("p.go", []byte(src), translations{"X": "X 是 x。\n\n\tcode\n"}, modeReplace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package p\n\nvar (\n\t// X 是 x。\n\t//\n\t//\tcode\n\tX = 1\n\tY = 2\n)\n\nfunc f() {\n\t// X is local.\n\tvar X int\n\t_ = X\n}\n"
+	if n != 1 || string(out) != want {
+		t.Errorf("rewrite = %d, %q, want 1, %q", n, out, want)
+	}
+}
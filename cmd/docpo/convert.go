@@ -0,0 +1,236 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/po"
+	"github.com/golang-china/golangdoc.translations/xliff"
+)
+
+const poHeader = "Content-Type: text/plain; charset=UTF-8\n" +
+	"Content-Transfer-Encoding: 8bit\n" +
+	"Language: zh_CN\n"
+
+// A message is the format independent form of an exported unit.
+type message struct {
+	id     string // unit identifier, see docstub.Package.IDs
+	source string // English text
+	target string // Chinese text
+	fuzzy  bool
+	ref    string // file:line
+	note   string // first line of the signature
+}
+
+// export writes the units of the packages matching patterns to w.
+func export(w io.Writer, root, format string, patterns []string) error {
+	var files [][]*message // messages of each stub file
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, root, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(patterns, pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ids := pkg.IDs()
+		i := 0
+		for _, f := range pkg.Files {
+			var msgs []*message
+			for _, u := range f.Units {
+				id := ids[i]
+				i++
+				if !u.Documented() {
+					continue
+				}
+				msgs = append(msgs, &message{
+					id:     id,
+					source: u.Source(),
+					target: u.Chinese,
+					fuzzy:  u.Fuzzy(),
+					ref:    filepath.ToSlash(f.Name) + ":" + strconv.Itoa(u.Pos.Line),
+					note:   strings.SplitN(u.Signature, "\n", 2)[0],
+				})
+			}
+			files = append(files, msgs)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "po":
+		return writePO(w, files)
+	case "xliff":
+		return writeXLIFF(w, files)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func writePO(w io.Writer, files [][]*message) error {
+	f := &po.File{Header: poHeader}
+	for _, msgs := range files {
+		for _, m := range msgs {
+			pm := &po.Message{
+				Extracted:  []string{m.note},
+				References: []string{m.ref},
+				Context:    m.id,
+				ID:         m.source,
+				Str:        m.target,
+			}
+			if m.fuzzy {
+				pm.Flags = []string{"fuzzy"}
+			}
+			f.Messages = append(f.Messages, pm)
+		}
+	}
+	return f.Write(w)
+}
+
+func writeXLIFF(w io.Writer, files [][]*message) error {
+	doc := new(xliff.Document)
+	for _, msgs := range files {
+		if len(msgs) == 0 {
+			continue
+		}
+		xf := &xliff.File{
+			Original:       strings.SplitN(msgs[0].ref, ":", 2)[0],
+			SourceLanguage: "en",
+			TargetLanguage: "zh-CN",
+			Datatype:       "x-go-doc",
+		}
+		for _, m := range msgs {
+			state := xliff.StateTranslated
+			switch {
+			case m.target == "":
+				state = xliff.StateNew
+			case m.fuzzy:
+				state = xliff.StateNeedsReview
+			}
+			xf.Units = append(xf.Units, &xliff.Unit{
+				ID:     m.id,
+				Source: xliff.Text{Space: "preserve", Text: m.source},
+				Target: &xliff.Target{State: state, Space: "preserve", Text: m.target},
+				Notes:  []string{m.note},
+			})
+		}
+		doc.Files = append(doc.Files, xf)
+	}
+	return doc.Write(w)
+}
+
+// read reads the messages of an exported file.
+func read(r io.Reader, format string) ([]*message, error) {
+	var msgs []*message
+	switch format {
+	case "po":
+		f, err := po.Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range f.Messages {
+			msgs = append(msgs, &message{id: m.Context, source: m.ID, target: m.Str, fuzzy: m.Fuzzy()})
+		}
+	case "xliff":
+		doc, err := xliff.Read(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range doc.Files {
+			for _, u := range f.Units {
+				m := &message{id: u.ID, source: u.Source.Text}
+				if u.Target != nil {
+					m.target = u.Target.Text
+					m.fuzzy = strings.HasPrefix(u.Target.State, "needs-review")
+				}
+				msgs = append(msgs, m)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	return msgs, nil
+}
+
+// importFile writes the translations read from r back into the stubs below
+// root, and returns the names of the files it changed.
+func importFile(r io.Reader, root, format string) ([]string, error) {
+	msgs, err := read(r, format)
+	if err != nil {
+		return nil, err
+	}
+	byPkg := make(map[string]map[string]*message)
+	for _, m := range msgs {
+		path, _ := docstub.SplitID(m.id)
+		if byPkg[path] == nil {
+			byPkg[path] = make(map[string]*message)
+		}
+		byPkg[path][m.id] = m
+	}
+	var paths []string
+	for path := range byPkg {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changed []string
+	for _, path := range paths {
+		c, err := importPackage(root, path, byPkg[path])
+		changed = append(changed, c...)
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func importPackage(root, importPath string, msgs map[string]*message) ([]string, error) {
+	fset := token.NewFileSet()
+	pkg := &docstub.Package{ImportPath: importPath, Dir: filepath.Join(root, filepath.FromSlash(importPath))}
+	var err error
+	if pkg.Files, err = docstub.ParseDir(fset, pkg.Dir); err != nil {
+		return nil, err
+	}
+	ids := pkg.IDs()
+	i := 0
+	var changed []string
+	for _, f := range pkg.Files {
+		var edits []docstub.Edit
+		for _, u := range f.Units {
+			m := msgs[ids[i]]
+			i++
+			if m == nil {
+				continue
+			}
+			if m.source != u.Source() {
+				log.Printf("%s: %s: English text changed since export; skipped", u.Pos, ids[i-1])
+				continue
+			}
+			edits = append(edits, u.Edit(m.target, m.fuzzy))
+		}
+		src := f.Apply(edits)
+		if bytes.Equal(src, f.Src) {
+			continue
+		}
+		if err := ioutil.WriteFile(f.Name, src, 0644); err != nil {
+			return changed, err
+		}
+		changed = append(changed, f.Name)
+	}
+	return changed, nil
+}
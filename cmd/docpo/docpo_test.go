@@ -0,0 +1,158 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// testPackages are copied from the stub tree for the tests. They cover
+// grouped declarations with documented specs (go/ast, os), English-only and
+// Chinese-only units (io) and platform specific stubs (syscall).
+var testPackages = []string{"strings", "io", "go/ast", "os", "syscall"}
+
+// copyTree copies the test packages into a temporary stub tree and returns
+// its root and the original contents of the stubs.
+func copyTree(t *testing.T) (root string, orig map[string][]byte) {
+	root, err := ioutil.TempDir("", "docpo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig = make(map[string][]byte)
+	for _, path := range testPackages {
+		src := filepath.Join("..", "..", "src", filepath.FromSlash(path))
+		dst := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			t.Fatal(err)
+		}
+		names, err := filepath.Glob(filepath.Join(src, docstub.StubPrefix+"*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			b, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			name = filepath.Join(dst, filepath.Base(name))
+			if err := ioutil.WriteFile(name, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+			orig[name] = b
+		}
+	}
+	return root, orig
+}
+
+func checkUnchanged(t *testing.T, orig map[string][]byte) {
+	for name, want := range orig {
+		got, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s changed", name)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{"po", "xliff"} {
+		root, orig := copyTree(t)
+		defer os.RemoveAll(root)
+
+		var buf bytes.Buffer
+		if err := export(&buf, root, format, nil); err != nil {
+			t.Fatalf("%s: export: %v", format, err)
+		}
+		exported := buf.String()
+		changed, err := importFile(strings.NewReader(exported), root, format)
+		if err != nil {
+			t.Fatalf("%s: import: %v", format, err)
+		}
+		if len(changed) > 0 {
+			t.Errorf("%s: import of an unchanged file changed %v", format, changed)
+		}
+		checkUnchanged(t, orig)
+
+		// Exporting again must give the same file.
+		buf.Reset()
+		if err := export(&buf, root, format, nil); err != nil {
+			t.Fatalf("%s: export: %v", format, err)
+		}
+		if buf.String() != exported {
+			t.Errorf("%s: second export differs", format)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	root, orig := copyTree(t)
+	defer os.RemoveAll(root)
+
+	var buf bytes.Buffer
+	if err := export(&buf, root, "po", []string{"strings", "io"}); err != nil {
+		t.Fatal(err)
+	}
+	po := buf.String()
+
+	// Translate an English-only unit, retranslate a unit and flag it fuzzy,
+	// and clear a translation.
+	replace := func(old, new string) {
+		if !strings.Contains(po, old) {
+			t.Fatalf("export has no %q", old)
+		}
+		po = strings.Replace(po, old, new, 1)
+	}
+	replace("msgctxt \"io.ErrNoProgress\"\nmsgid \"\"\n\"ErrNoProgress is returned by some clients of an io.Reader when many calls to\\n\"\n\"Read have failed to return any data or error, usually the sign of a broken\\n\"\n\"io.Reader implementation.\\n\"\nmsgstr \"\"",
+		"msgctxt \"io.ErrNoProgress\"\nmsgid \"\"\n\"ErrNoProgress is returned by some clients of an io.Reader when many calls to\\n\"\n\"Read have failed to return any data or error, usually the sign of a broken\\n\"\n\"io.Reader implementation.\\n\"\nmsgstr \"\"\n\"某些 io.Reader 的客户端在多次调用 Read 都未能返回任何数据或错误时，\\n\"\n\"就会返回 ErrNoProgress。\\n\"")
+	replace("msgctxt \"strings.Contains\"", "#, fuzzy\nmsgctxt \"strings.Contains\"")
+	replace("msgstr \"判断字符串s是否包含子串substr。\\n\"", "msgstr \"判断 s 是否包含子串 substr。\\n\"")
+	replace("msgstr \"判断字符串s是否包含utf-8码值r。\\n\"", "msgstr \"\"")
+
+	changed, err := importFile(strings.NewReader(po), root, "po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 {
+		t.Errorf("changed %v, want the strings and io stubs", changed)
+	}
+
+	fset := token.NewFileSet()
+	for _, tt := range []struct {
+		pkg, name, chinese string
+		markers            []string
+	}{
+		{"io", "ErrNoProgress", "某些 io.Reader 的客户端在多次调用 Read 都未能返回任何数据或错误时，\n就会返回 ErrNoProgress。\n", nil},
+		{"io", "ErrShortBuffer", "ErrShortBuffer 意为所需读取的缓存比提供的长。\n", nil},
+		{"strings", "Contains", "判断 s 是否包含子串 substr。\n", []string{docstub.Fuzzy}},
+		{"strings", "ContainsRune", "", []string{docstub.Untranslated}},
+	} {
+		f, err := docstub.ParseFile(fset, filepath.Join(root, tt.pkg, "doc_zh_CN.go"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := f.Lookup(tt.name)
+		if u.Chinese != tt.chinese || strings.Join(u.Markers, ",") != strings.Join(tt.markers, ",") {
+			t.Errorf("%s.%s: got %q %v, want %q %v", tt.pkg, tt.name, u.Chinese, u.Markers, tt.chinese, tt.markers)
+		}
+	}
+
+	// The other stubs are untouched.
+	for name := range orig {
+		if strings.Contains(filepath.ToSlash(name), "/io/") || strings.Contains(filepath.ToSlash(name), "/strings/") {
+			delete(orig, name)
+		}
+	}
+	checkUnchanged(t, orig)
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docpo converts the translation units of the doc stubs to and from gettext
+// PO files and XLIFF 1.2 documents, so that they can be translated with PO
+// editors and CAT tools.
+//
+// Usage:
+//
+//	docpo export [flags] [importpath ...]
+//	docpo import [flags] file
+//
+// Export writes every documented declaration of the src/**/doc_zh_CN.go stubs
+// as a message whose msgctxt (trans-unit id in XLIFF) is
+// "importpath.Identifier", such as "strings.Reader.Len", or the import path
+// alone for the package doc. The msgid is the English comment and the msgstr
+// the Chinese one. Fuzzy translations (see docgen -merge) are flagged fuzzy
+// (state needs-review-translation in XLIFF).
+//
+// Import writes the translations of an exported file back into the stubs.
+// Only the Chinese comments of the changed units are rewritten; signatures,
+// build constraints and formatting stay as they are, and importing an
+// unchanged file leaves the stubs identical byte for byte. Messages whose
+// msgid no longer matches the English comment of the stub are skipped.
+//
+// The flags are:
+//
+//	-format po|xliff
+//		file format (default: by file name, else po)
+//	-o file
+//		export to file instead of standard output
+//	-src dir
+//		root of the stub tree (default "src")
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+var (
+	format  = flag.String("format", "", "file format: po or xliff")
+	outFile = flag.String("o", "", "export to `file` instead of standard output")
+	srcDir  = flag.String("src", "src", "root of the stub tree")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docpo export [flags] [importpath ...]\n")
+	fmt.Fprintf(os.Stderr, "       docpo import [flags] file\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docpo: ")
+	flag.Usage = usage
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd := os.Args[1]
+	flag.CommandLine.Parse(os.Args[2:])
+
+	switch cmd {
+	case "export":
+		w := io.Writer(os.Stdout)
+		if *outFile != "" {
+			f, err := os.Create(*outFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := export(w, *srcDir, fileFormat(*outFile), flag.Args()); err != nil {
+			log.Fatal(err)
+		}
+	case "import":
+		if flag.NArg() != 1 {
+			usage()
+		}
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		changed, err := importFile(f, *srcDir, fileFormat(flag.Arg(0)))
+		for _, name := range changed {
+			fmt.Println(name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		usage()
+	}
+}
+
+// fileFormat returns the format selected by the -format flag, or guessed
+// from the file name.
+func fileFormat(name string) string {
+	if *format != "" {
+		return *format
+	}
+	switch filepath.Ext(name) {
+	case ".xlf", ".xliff":
+		return "xliff"
+	}
+	return "po"
+}
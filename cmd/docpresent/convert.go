@@ -0,0 +1,192 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang-china/golangdoc.translations/po"
+	"github.com/golang-china/golangdoc.translations/presentdoc"
+)
+
+const poHeader = "Content-Type: text/plain; charset=UTF-8\n" +
+	"Content-Transfer-Encoding: 8bit\n" +
+	"Language: zh_CN\n"
+
+// presentExts are the extensions of the present files.
+var presentExts = map[string]bool{".article": true, ".slide": true}
+
+// walk calls fn for the present files of the paths, with their names
+// relative to the path, or their base names if the path is a file.
+func walk(paths []string, fn func(name, rel string) error) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if err := fn(path, filepath.Base(path)); err != nil {
+				return err
+			}
+			continue
+		}
+		err = filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !presentExts[filepath.Ext(name)] {
+				return err
+			}
+			rel, err := filepath.Rel(path, name)
+			if err != nil {
+				return err
+			}
+			return fn(name, rel)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse reads and parses a present file.
+func parse(name string) (*presentdoc.Document, error) {
+	src, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return presentdoc.Parse(name, src), nil
+}
+
+// extract writes the sentences of the files to w, with the translations of
+// the PO file merge, if any.
+func extract(w io.Writer, paths []string, merge string) error {
+	var old map[string]*po.Message
+	if merge != "" {
+		var err error
+		if old, err = readPO(merge); err != nil {
+			return err
+		}
+	}
+	f := &po.File{Header: poHeader}
+	msgs := make(map[string]*po.Message)
+	err := walk(paths, func(name, _ string) error {
+		doc, err := parse(name)
+		if err != nil {
+			return err
+		}
+		for _, b := range doc.Blocks {
+			if !b.IsText() {
+				continue
+			}
+			ref := filepath.ToSlash(name) + ":" + strconv.Itoa(b.Line)
+			for _, s := range b.Sentences() {
+				if m := msgs[s]; m != nil {
+					m.References = append(m.References, ref)
+					continue
+				}
+				m := &po.Message{
+					Extracted:  []string{b.Kind.String()},
+					References: []string{ref},
+					ID:         s,
+				}
+				if o := old[s]; o != nil {
+					m.Comments, m.Flags, m.Str = o.Comments, o.Flags, o.Str
+				}
+				msgs[s] = m
+				f.Messages = append(f.Messages, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// readPO returns the messages of a PO file, by msgid.
+func readPO(name string) (map[string]*po.Message, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	f, err := po.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make(map[string]*po.Message)
+	for _, m := range f.Messages {
+		msgs[m.ID] = m
+	}
+	return msgs, nil
+}
+
+// build writes the files translated by the PO file, below out or in place,
+// and returns the names of the files it changed.
+func build(poFile string, paths []string, out string) ([]string, error) {
+	msgs, err := readPO(poFile)
+	if err != nil {
+		return nil, err
+	}
+	tr := func(s string) string {
+		if m := msgs[s]; m != nil && !m.Fuzzy() {
+			return m.Str
+		}
+		return ""
+	}
+	var changed []string
+	err = walk(paths, func(name, rel string) error {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		data := presentdoc.Parse(name, src).Translate(tr)
+		dst := name
+		if out != "" {
+			dst = filepath.Join(out, rel)
+		}
+		if old, err := ioutil.ReadFile(dst); err == nil && bytes.Equal(old, data) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dst, data, 0644); err != nil {
+			return err
+		}
+		changed = append(changed, dst)
+		return nil
+	})
+	return changed, err
+}
+
+// check returns the problems of the .code and .play directives of the
+// files, as "file:line: message" lines.
+func check(paths []string) ([]string, error) {
+	var probs []string
+	err := walk(paths, func(name, _ string) error {
+		doc, err := parse(name)
+		if err != nil {
+			return err
+		}
+		for _, c := range doc.Code() {
+			src, err := ioutil.ReadFile(filepath.Join(filepath.Dir(name), c.File))
+			if err == nil {
+				_, err = c.Select(src)
+			}
+			if err != nil {
+				probs = append(probs, fmt.Sprintf("%s:%d: .%s %s: %v", name, c.Line, c.Cmd, c.File, err))
+			}
+		}
+		return nil
+	})
+	return probs, err
+}
@@ -0,0 +1,144 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/po"
+)
+
+const testArticle = `Flow control
+Learn how to control the flow of your code.
+
+The Go Authors
+
+* For
+
+Go has only one looping construct, the ` + "`for`" + ` loop.
+
+.play flowcontrol/for.go
+
+* Congratulations!
+
+You finished this lesson! Go has only one looping construct, the ` + "`for`" + ` loop.
+`
+
+func TestExtractBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docpresent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	content := filepath.Join(dir, "content")
+	if err := os.Mkdir(content, 0755); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(content, "flowcontrol.article")
+	if err := ioutil.WriteFile(name, []byte(testArticle), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := extract(&buf, []string{content}, ""); err != nil {
+		t.Fatal(err)
+	}
+	f, err := po.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, m := range f.Messages {
+		ids = append(ids, m.ID)
+		switch m.ID {
+		case "Go has only one looping construct, the `for` loop.":
+			m.Str = "Go 只有一种循环结构：`for` 循环。"
+			if len(m.References) != 2 {
+				t.Errorf("references = %q, want 2", m.References)
+			}
+		case "For":
+			m.Str = "for"
+			m.Flags = []string{"fuzzy"}
+		}
+	}
+	want := "Flow control|Learn how to control the flow of your code.|For|" +
+		"Go has only one looping construct, the `for` loop.|Congratulations!|You finished this lesson!"
+	if got := strings.Join(ids, "|"); got != want {
+		t.Errorf("messages = %s, want %s", got, want)
+	}
+
+	poFile := filepath.Join(dir, "tour.po")
+	buf.Reset()
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(poFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	changed, err := build(poFile, []string{content}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 {
+		t.Fatalf("changed %q, want 1 file", changed)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(out, "flowcontrol.article"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantArticle := strings.Replace(testArticle, "Go has only one looping construct, the `for` loop.", "Go 只有一种循环结构：`for` 循环。", -1)
+	if string(data) != wantArticle {
+		t.Errorf("built:\n%s\nwant:\n%s", data, wantArticle)
+	}
+
+	// Extracting again keeps the translations.
+	buf.Reset()
+	if err := extract(&buf, []string{content}, poFile); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "msgstr \"Go 只有一种循环结构：`for` 循环。\"") {
+		t.Errorf("extract -merge lost the translation:\n%s", buf.String())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docpresent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"talk.slide": "Talk\n\n* Slide\n\n.code talk/main.go /START/,/END/ HLmain\n.play talk/main.go /func main/,/^}/\n.code talk/gone.go\n",
+		// The START OMIT comment was translated.
+		"talk/main.go": "package main\n\n// 开始 OMIT\nfunc main() { // HLmain\n}\n\n// END OMIT\n",
+	}
+	for name, src := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	probs, err := check([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide := filepath.Join(dir, "talk.slide")
+	want := []string{
+		slide + ":5: .code talk/main.go: address /START/,/END/: no match for /START/",
+		slide + ":7: .code talk/gone.go: open " + filepath.Join(dir, "talk/gone.go") + ": no such file or directory",
+	}
+	if strings.Join(probs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("check:\n%s\nwant:\n%s", strings.Join(probs, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docpresent translates the present files of the tour and the talks, the
+// .article files of tour/zh_CN/content and the .slide files of talks/zh_CN,
+// sentence by sentence through gettext PO files.
+//
+// Usage:
+//
+//	docpresent extract [flags] [path ...]
+//	docpresent build [flags] file.po [path ...]
+//	docpresent check [path ...]
+//
+// The paths, by default tour/zh_CN/content and talks/zh_CN, are present files
+// or directories, which are searched for them.
+//
+// Extract writes the sentences of the titles, headings, paragraphs and list
+// items of the files as the messages of a PO file; see package presentdoc.
+// The directives, such as ".play basics/packages.go" or
+// ".code advconc/fakemain/fakemain.go /STARTFETCHCASE /,/STOPFETCHCASE / HLfetch",
+// the #appengine: lines, the code blocks and the authors are not extracted.
+// A sentence found several times is one message with the references of all.
+// With -merge, the translations of an earlier PO file are kept, so that the
+// English files of a new version can be extracted again without losing work.
+//
+// Build writes the files with the sentences translated by the PO file, and
+// prints their names. Fuzzy and empty translations are not used; an
+// untranslated sentence stays in English. Everything but the text, the
+// directives in particular, is written as it is.
+//
+// Check checks that the .code and .play directives of the files still show
+// what they should once the files and the programs they show are translated:
+// the program must exist, the address must select lines of it, such as the
+// lines between the /START/ and /END/ comments, and the lines must have the
+// HL marker to highlight, if any. Check exits with status 1 if there are
+// problems.
+//
+// The flags are:
+//
+//	-merge file
+//		extract: keep the translations of the PO file
+//	-o path
+//		extract: write to file instead of standard output;
+//		build: write the files below dir instead of rewriting them
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+var (
+	mergeFile = flag.String("merge", "", "keep the translations of the PO `file`")
+	outPath   = flag.String("o", "", "output `path`: PO file for extract, directory for build")
+)
+
+var defaultPaths = []string{"tour/zh_CN/content", "talks/zh_CN"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docpresent extract [flags] [path ...]\n")
+	fmt.Fprintf(os.Stderr, "       docpresent build [flags] file.po [path ...]\n")
+	fmt.Fprintf(os.Stderr, "       docpresent check [path ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docpresent: ")
+	flag.Usage = usage
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd := os.Args[1]
+	flag.CommandLine.Parse(os.Args[2:])
+
+	switch cmd {
+	case "extract":
+		w := io.Writer(os.Stdout)
+		if *outPath != "" {
+			f, err := os.Create(*outPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := extract(w, paths(flag.Args()), *mergeFile); err != nil {
+			log.Fatal(err)
+		}
+	case "build":
+		if flag.NArg() < 1 {
+			usage()
+		}
+		changed, err := build(flag.Arg(0), paths(flag.Args()[1:]), *outPath)
+		for _, name := range changed {
+			fmt.Println(name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "check":
+		probs, err := check(paths(flag.Args()))
+		for _, p := range probs {
+			fmt.Println(p)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(probs) > 0 {
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
+
+func paths(args []string) []string {
+	if len(args) == 0 {
+		return defaultPaths
+	}
+	return args
+}
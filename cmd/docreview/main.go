@@ -0,0 +1,159 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docreview lists the translations of the stubs that need a review, and
+// records reviews.
+//
+// A review is recorded in the stub, after the Chinese comment it concerns,
+// as a marker line with the reviewer, the Go version of the English text and
+// a hash of that text:
+//
+//	// Contains reports whether substr is within s.
+//
+//	// Contains 判断字符串 s 是否包含子串 substr。
+//	//zh:reviewed chai2010 go1.5 1c4f7e0a
+//	func Contains(s, substr string) bool
+//
+// gofmt keeps such lines and godoc does not show them. A review is outdated
+// once the translation is flagged fuzzy, once docgen -merge brings a new
+// English text, or, with -go, if it is of an older Go version. Changing the
+// Chinese text, other than rewrapping it, drops the review.
+//
+// Usage:
+//
+//	docreview [flags] [importpath ...]
+//
+// By default, docreview prints the translated declarations whose review is
+// missing or outdated, as "file:line: id: state". An import path restricts
+// the list to that package; a path ending in "/..." also includes the
+// packages below it. With -mark, it records instead the review of these
+// declarations, and prints the names of the stubs it rewrites. The flags are:
+//
+//	-src dir
+//		root of the stub tree (default "src")
+//	-go version
+//		Go version of the English texts, such as go1.5; required by -mark
+//	-mark reviewer
+//		record the review of the declarations by reviewer
+//	-run regexp
+//		only the declarations whose id, such as strings.Reader.Len, matches
+//	-v
+//		also print the reviewed declarations, with their reviewer
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	srcDir    = flag.String("src", "src", "root of the stub tree")
+	goVersion = flag.String("go", "", "Go `version` of the English texts, such as go1.5")
+	reviewer  = flag.String("mark", "", "record the review of the declarations by `reviewer`")
+	run       = flag.String("run", "", "only the declarations whose id matches `regexp`")
+	verbose   = flag.Bool("v", false, "also print the reviewed declarations")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docreview [flags] [importpath ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docreview: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *reviewer != "" && (*goVersion == "" || strings.ContainsAny(*reviewer, " \t")) {
+		log.Fatal("-mark needs -go and a reviewer name without spaces")
+	}
+	var match *regexp.Regexp
+	if *run != "" {
+		var err error
+		if match, err = regexp.Compile(*run); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, *srcDir, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(flag.Args(), pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if *reviewer != "" {
+			return mark(pkg, match, *reviewer, *goVersion)
+		}
+		list(os.Stdout, pkg, match, *goVersion, *verbose)
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// units calls fn for the translated units of pkg whose id matches, with
+// their id and review state.
+func units(pkg *docstub.Package, match *regexp.Regexp, goVersion string, fn func(u *docstub.Unit, id string, state docstub.ReviewState, reason string)) {
+	ids := pkg.IDs()
+	for i, u := range pkg.Units() {
+		if match != nil && !match.MatchString(ids[i]) {
+			continue
+		}
+		if state, reason := u.ReviewState(goVersion); state != docstub.NotTranslated {
+			fn(u, ids[i], state, reason)
+		}
+	}
+}
+
+// list prints the units of pkg that need a review, or all the translated
+// ones if verbose is set.
+func list(w io.Writer, pkg *docstub.Package, match *regexp.Regexp, goVersion string, verbose bool) {
+	units(pkg, match, goVersion, func(u *docstub.Unit, id string, state docstub.ReviewState, reason string) {
+		msg := state.String()
+		switch {
+		case state == docstub.UpToDate && !verbose:
+			return
+		case state == docstub.UpToDate:
+			r := u.Review()
+			msg += " by " + r.Reviewer + " against " + r.GoVersion
+		case reason != "":
+			msg += ": " + reason
+		}
+		fmt.Fprintf(w, "%s:%d: %s: %s\n", u.Pos.Filename, u.Pos.Line, id, msg)
+	})
+}
+
+// mark records the review of the units of pkg that need one, and prints
+// the names of the files it rewrites.
+func mark(pkg *docstub.Package, match *regexp.Regexp, reviewer, goVersion string) error {
+	edits := make(map[string][]docstub.Edit)
+	units(pkg, match, goVersion, func(u *docstub.Unit, id string, state docstub.ReviewState, reason string) {
+		if state != docstub.UpToDate {
+			edits[u.Pos.Filename] = append(edits[u.Pos.Filename], u.MarkReviewed(reviewer, goVersion))
+		}
+	})
+	for _, f := range pkg.Files {
+		if len(edits[f.Name]) == 0 {
+			continue
+		}
+		if err := ioutil.WriteFile(f.Name, f.Apply(edits[f.Name]), 0644); err != nil {
+			return err
+		}
+		fmt.Println(f.Name)
+	}
+	return nil
+}
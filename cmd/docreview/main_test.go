@@ -0,0 +1,102 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+const testStub = `//go:build ignore
+// +build ignore
+
+// Package p is a test.
+
+// p 包用于测试。
+package p
+
+// F does f.
+
+// F 做 f。
+//
+//zh:fuzzy
+func F()
+
+// G does g.
+
+// G 做 g。
+func G()
+
+// H does h.
+//
+//zh:untranslated
+func H()
+`
+
+func TestMark(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docreview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "p"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "p", "doc_zh_CN.go")
+	if err := ioutil.WriteFile(name, []byte(testStub), 0644); err != nil {
+		t.Fatal(err)
+	}
+	load := func() *docstub.Package {
+		var pkg *docstub.Package
+		err := docstub.Walk(token.NewFileSet(), dir, func(p *docstub.Package, err error) error {
+			pkg = p
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg
+	}
+
+	var buf bytes.Buffer
+	list(&buf, load(), nil, "", false)
+	want := name + ":7: p: unreviewed\n" +
+		name + ":14: p.F: unreviewed\n" +
+		name + ":19: p.G: unreviewed\n"
+	if buf.String() != want {
+		t.Errorf("list:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if err := mark(load(), regexp.MustCompile(`\.[FG]$`), "chai2010", "go1.5"); err != nil {
+		t.Fatal(err)
+	}
+	src, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gofmt, err := format.Source(src); err != nil || !bytes.Equal(gofmt, src) {
+		t.Errorf("gofmt changes the marked stub:\n%s", src)
+	}
+	buf.Reset()
+	list(&buf, load(), nil, "go1.5", true)
+	want = name + ":7: p: unreviewed\n" +
+		name + ":14: p.F: reviewed by chai2010 against go1.5\n" +
+		name + ":21: p.G: reviewed by chai2010 against go1.5\n"
+	if buf.String() != want {
+		t.Errorf("list after mark:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	pkg := load()
+	if u := pkg.Files[0].Lookup("F"); u.Fuzzy() {
+		t.Errorf("F is still fuzzy after its review")
+	}
+}
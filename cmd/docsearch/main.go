@@ -0,0 +1,153 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docsearch searches the translated documentation in English and in
+// Chinese: the declarations of the src/**/doc_zh_CN.go stubs and the
+// paragraphs of the doc/zh_CN HTML documents.
+//
+// Usage:
+//
+//	docsearch [flags] query
+//	docsearch [flags] -http addr
+//
+// A query is a list of English words, Chinese text or identifiers, such as
+//
+//	docsearch 切片 容量
+//	docsearch goroutine leak
+//	docsearch strings.Index
+//
+// Declarations named by the query come first, then the documents holding
+// most of its words, by relevance. Each hit is printed with a snippet of its
+// English and of its Chinese text. With -http, docsearch serves the index:
+//
+//	GET /search?q=query&n=10
+//
+// returns the hits as JSON. The flags are:
+//
+//	-src dir
+//		root of the stub tree (default "src")
+//	-doc dir
+//		directory of the HTML documents (default "doc/zh_CN")
+//	-n count
+//		number of hits (default 10)
+//	-json
+//		print JSON instead of text
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/search"
+)
+
+var (
+	srcDir   = flag.String("src", "src", "root of the stub tree")
+	docDir   = flag.String("doc", "doc/zh_CN", "directory of the HTML documents")
+	count    = flag.Int("n", 10, "number of hits")
+	jsonOut  = flag.Bool("json", false, "print JSON instead of text")
+	httpAddr = flag.String("http", "", "serve the index over HTTP on `addr`")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docsearch [flags] query\n")
+	fmt.Fprintf(os.Stderr, "       docsearch [flags] -http addr\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docsearch: ")
+	flag.Usage = usage
+	flag.Parse()
+	if (*httpAddr == "") == (flag.NArg() == 0) {
+		usage()
+	}
+
+	index := search.New()
+	if err := index.AddStubs(*srcDir); err != nil {
+		log.Fatal(err)
+	}
+	if err := index.AddHTML(*docDir); err != nil {
+		log.Fatal(err)
+	}
+
+	if *httpAddr != "" {
+		log.Printf("%d documents; serving on %s", len(index.Docs), *httpAddr)
+		log.Fatal(http.ListenAndServe(*httpAddr, &server{index}))
+	}
+	query := strings.Join(flag.Args(), " ")
+	hits := index.Search(query, *count)
+	if *jsonOut {
+		b, err := json.MarshalIndent(result{Query: query, Hits: hits}, "", "\t")
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Stdout.Write(append(b, '\n'))
+		return
+	}
+	writeHits(os.Stdout, hits)
+}
+
+// A result is the hits for a query.
+type result struct {
+	Query string        `json:"query"`
+	Hits  []*search.Hit `json:"hits"`
+}
+
+func writeHits(w io.Writer, hits []*search.Hit) {
+	for _, h := range hits {
+		fmt.Fprintf(w, "%s\t%s\n", h.ID, h.Source)
+		if h.Title != "" {
+			fmt.Fprintf(w, "\t[%s]\n", h.Title)
+		}
+		for _, s := range []string{h.EnglishSnippet, h.ChineseSnippet} {
+			if s != "" {
+				fmt.Fprintf(w, "\t%s\n", s)
+			}
+		}
+	}
+}
+
+// maxHits bounds the n parameter of a request.
+const maxHits = 100
+
+// server serves the hits of an index as JSON.
+type server struct {
+	index *search.Index
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/search" {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.FormValue("q")
+	if strings.TrimSpace(q) == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+	n := *count
+	if v := r.FormValue("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 0 || n > maxHits {
+			http.Error(w, "bad n: "+v, http.StatusBadRequest)
+			return
+		}
+	}
+	hits := s.index.Search(q, n)
+	if hits == nil {
+		hits = []*search.Hit{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(result{Query: q, Hits: hits})
+}
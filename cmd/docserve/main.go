@@ -0,0 +1,164 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docserve serves the package pages of the doc stubs for preview, without
+// golangdoc.
+//
+// It reads the src/<importpath>/doc_zh_CN.go stubs through go/doc at every
+// request, and shows for each declaration the Chinese text, the English
+// text, or both side by side. The lang query parameter, "zh", "en" or
+// "both", selects the text; without it, the Accept-Language header of the
+// browser does. The pages reload themselves when a stub changes.
+//
+// Usage:
+//
+//	docserve [flags]
+//
+// The flags are:
+//
+//	-http addr
+//		HTTP service address (default "localhost:6060")
+//	-poll d
+//		interval at which the stubs are checked for changes (default 500ms)
+//	-src dir
+//		root of the stub tree (default "src")
+//	-static dir
+//		godoc static files, served at /lib/godoc/ (default "static/zh_CN")
+//
+// The goos and goarch query parameters select the platform specific stubs
+// shown along with doc_zh_CN.go; they default to the platform of docserve.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	httpAddr  = flag.String("http", "localhost:6060", "HTTP service `address`")
+	poll      = flag.Duration("poll", 500*time.Millisecond, "interval at which the stubs are checked for changes")
+	srcDir    = flag.String("src", "src", "root of the stub tree")
+	staticDir = flag.String("static", "static/zh_CN", "godoc static files")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docserve [flags]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docserve: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 {
+		usage()
+	}
+
+	w := newWatcher(*srcDir)
+	go w.run(*poll)
+	http.Handle("/", &server{root: *srcDir, watcher: w})
+	http.Handle("/-/reload", w)
+	http.Handle("/lib/godoc/", http.StripPrefix("/lib/godoc/", http.FileServer(http.Dir(*staticDir))))
+	log.Printf("serving %s at http://%s/", *srcDir, *httpAddr)
+	log.Fatal(http.ListenAndServe(*httpAddr, nil))
+}
+
+// A server serves the index of the packages at / and the package pages at
+// /pkg/<importpath>/.
+type server struct {
+	root    string
+	watcher *watcher
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "/pkg/":
+		s.serveIndex(w, r)
+	case strings.HasPrefix(r.URL.Path, "/pkg/"):
+		s.servePackage(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	var paths []string
+	err := filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !docstub.IsStubFile(path) {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if len(paths) == 0 || paths[len(paths)-1] != rel {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.Strings(paths)
+	render(w, indexTemplate, paths)
+}
+
+func (s *server) servePackage(w http.ResponseWriter, r *http.Request) {
+	importPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/pkg/"), "/")
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		u := r.URL.Path + "/"
+		if r.URL.RawQuery != "" {
+			u += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, u, http.StatusMovedPermanently)
+		return
+	}
+	goos, goarch := r.FormValue("goos"), r.FormValue("goarch")
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	version, _ := s.watcher.current()
+	p, err := loadPage(s.root, importPath, goos, goarch)
+	switch {
+	case os.IsNotExist(err):
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		// Show the error, and reload once it is fixed.
+		p = &page{ImportPath: importPath, Name: importPath, Overview: &decl{}, Error: err.Error()}
+	}
+	p.Version = version
+	p.setMode(pageMode(r))
+	render(w, pageTemplate, p)
+}
+
+func render(w http.ResponseWriter, t *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Vary", "Accept-Language")
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,341 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// Modes of a page: the Chinese text, the English text, or both side by
+// side.
+const (
+	modeZh   = "zh"
+	modeEn   = "en"
+	modeBoth = "both"
+)
+
+// pageMode returns the mode of the page asked for by r: the lang query
+// parameter, or else the language the Accept-Language header prefers among
+// Chinese and English, or else both.
+func pageMode(r *http.Request) string {
+	switch lang := r.URL.Query().Get("lang"); lang {
+	case modeZh, modeEn, modeBoth:
+		return lang
+	}
+	best, bestQ := modeBoth, 0.0
+	for _, s := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, q := s, 1.0
+		if i := strings.Index(s, ";"); i >= 0 {
+			tag = s[:i]
+			if v := strings.TrimSpace(s[i+1:]); strings.HasPrefix(v, "q=") {
+				var err error
+				if q, err = strconv.ParseFloat(v[2:], 64); err != nil {
+					continue
+				}
+			}
+		}
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		var mode string
+		switch {
+		case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+			mode = modeZh
+		case tag == "en" || strings.HasPrefix(tag, "en-"):
+			mode = modeEn
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = mode, q
+		}
+	}
+	return best
+}
+
+// A decl is a declaration of a package page with its doc comments.
+type decl struct {
+	ID       string // anchor, "" for none
+	Heading  string // "func F", "type T"; "" for constants and variables
+	Sub      bool   // declared along with a type
+	Code     string
+	English  template.HTML
+	Chinese  template.HTML
+	Fuzzy    bool // Chinese text marked fuzzy
+	Untransl bool // English text with no Chinese text
+	Mode     string
+}
+
+// A section lists the declarations of one kind.
+type section struct {
+	ID, Title string
+	Decls     []*decl
+}
+
+// A page is a package page.
+type page struct {
+	ImportPath string
+	Name       string
+	Files      []string
+	Mode       string
+	Overview   *decl
+	Sections   []*section
+	Version    int    // of the stub tree, for live reload
+	Error      string // why the stubs could not be read
+}
+
+// setMode sets the mode of the page and its declarations.
+func (p *page) setMode(mode string) {
+	p.Mode = mode
+	p.Overview.Mode = mode
+	for _, s := range p.Sections {
+		for _, d := range s.Decls {
+			d.Mode = mode
+		}
+	}
+}
+
+// stubFiles returns the stubs of dir shown for goos and goarch: the generic
+// stub and the stubs of the target. If there are none, as for package
+// syscall, whose only stubs are platform specific, it returns all.
+func stubFiles(dir, goos, goarch string) ([]string, error) {
+	all, err := filepath.Glob(filepath.Join(dir, docstub.StubPrefix+"*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(all)
+	var stubs, files []string
+	for _, name := range all {
+		if !docstub.IsStubFile(name) {
+			continue
+		}
+		stubs = append(stubs, name)
+		g, a := docstub.StubTarget(name)
+		if (g == "" || g == goos) && (a == "" || a == goarch) {
+			files = append(files, name)
+		}
+	}
+	if len(files) == 0 {
+		return stubs, nil
+	}
+	return files, nil
+}
+
+// loadPage reads the stubs of the package importPath below root, for goos
+// and goarch, through go/doc.
+func loadPage(root, importPath, goos, goarch string) (*page, error) {
+	dir := filepath.Join(root, filepath.FromSlash(importPath))
+	names, err := stubFiles(dir, goos, goarch)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	fset := token.NewFileSet()
+	astPkg := &ast.Package{Files: make(map[string]*ast.File)}
+	units := make(map[ast.Node]*docstub.Unit)
+	byName := make(map[string]*docstub.Unit)
+	var overview *docstub.Unit
+	for _, name := range names {
+		f, err := docstub.ParseFile(fset, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		astPkg.Name = f.Package
+		astPkg.Files[name] = f.AST
+		for _, u := range f.Units {
+			if u.Kind == docstub.PackageClause {
+				if overview == nil || u.Documented() {
+					overview = u
+				}
+				continue
+			}
+			units[u.Node] = u
+			if byName[u.Name] == nil {
+				byName[u.Name] = u
+			}
+		}
+	}
+	// unit returns the unit of a go/doc declaration: go/doc makes new
+	// declarations for the types of a group, which are found by name.
+	unit := func(node ast.Node, name string) *docstub.Unit {
+		if u := units[node]; u != nil {
+			return u
+		}
+		return byName[name]
+	}
+
+	pkg := doc.New(astPkg, importPath, doc.AllDecls)
+	p := &page{
+		ImportPath: importPath,
+		Name:       pkg.Name,
+		Files:      names,
+		Overview:   newDecl(fset, nil, "", "", overview),
+	}
+	values := func(s *section, values []*doc.Value, sub bool) {
+		for _, v := range values {
+			d := newDecl(fset, v.Decl, "", "", unit(v.Decl, v.Names[0]))
+			d.Sub = sub
+			s.Decls = append(s.Decls, d)
+		}
+	}
+	funcs := func(s *section, funcs []*doc.Func, sub bool) {
+		for _, f := range funcs {
+			name, heading := f.Name, "func "+f.Name
+			if f.Recv != "" {
+				name = docstub.RecvTypeName(f.Decl.Recv.List[0].Type) + "." + f.Name
+				heading = "func (" + f.Recv + ") " + f.Name
+			}
+			d := newDecl(fset, f.Decl, name, heading, unit(f.Decl, name))
+			d.Sub = sub
+			s.Decls = append(s.Decls, d)
+		}
+	}
+	consts := &section{ID: "pkg-constants", Title: "常量 Constants"}
+	values(consts, pkg.Consts, false)
+	vars := &section{ID: "pkg-variables", Title: "变量 Variables"}
+	values(vars, pkg.Vars, false)
+	fns := &section{ID: "pkg-functions", Title: "函数 Functions"}
+	funcs(fns, pkg.Funcs, false)
+	types := &section{ID: "pkg-types", Title: "类型 Types"}
+	for _, t := range pkg.Types {
+		types.Decls = append(types.Decls, newDecl(fset, t.Decl, t.Name, "type "+t.Name, unit(t.Decl, t.Name)))
+		values(types, t.Consts, true)
+		values(types, t.Vars, true)
+		funcs(types, t.Funcs, true)
+		funcs(types, t.Methods, true)
+	}
+	for _, s := range []*section{consts, vars, fns, types} {
+		if len(s.Decls) > 0 {
+			p.Sections = append(p.Sections, s)
+		}
+	}
+	return p, nil
+}
+
+// newDecl returns the declaration node, documented by u, with the anchor id
+// and the heading. The node is printed without its doc comment.
+func newDecl(fset *token.FileSet, node ast.Node, id, heading string, u *docstub.Unit) *decl {
+	d := &decl{ID: id, Heading: heading}
+	switch n := node.(type) {
+	case *ast.GenDecl:
+		n.Doc = nil
+		d.Code = docstub.FormatNode(fset, n)
+	case *ast.FuncDecl:
+		n.Doc = nil
+		n.Body = nil
+		d.Code = docstub.FormatNode(fset, n)
+	}
+	if u != nil {
+		d.English = commentHTML(u.English)
+		d.Chinese = commentHTML(u.Chinese)
+		d.Fuzzy = u.Fuzzy()
+		d.Untransl = u.English != "" && !u.Translated()
+	}
+	return d
+}
+
+// commentHTML formats doc text the way godoc does.
+func commentHTML(text string) template.HTML {
+	var buf bytes.Buffer
+	doc.ToHTML(&buf, text, nil)
+	return template.HTML(buf.String())
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+<title>{{.ImportPath}} - Go 编程语言</title>
+<link type="text/css" rel="stylesheet" href="/lib/godoc/style.css">
+<style>
+table.bilingual { width: 100%; table-layout: fixed; border-collapse: collapse; }
+table.bilingual td { width: 50%; vertical-align: top; padding: 0 10px 0 0; }
+.untranslated { color: #888; }
+.untranslated:before, .fuzzy:before { font-size: small; background: #FFE0B0; padding: 0 4px; }
+.untranslated:before { content: "未翻译"; }
+.fuzzy:before { content: "待校对"; }
+h3.sub { margin-left: 20px; }
+</style>
+</head>
+<body>
+<div id="topbar"><div class="container">
+<div id="menu">
+<a href="?lang=zh"{{if eq .Mode "zh"}} class="active"{{end}}>中文</a>
+<a href="?lang=en"{{if eq .Mode "en"}} class="active"{{end}}>English</a>
+<a href="?lang=both"{{if eq .Mode "both"}} class="active"{{end}}>对照</a>
+</div>
+<div id="heading"><a href="/">译文预览</a></div>
+</div></div>
+<div id="page"><div class="container">
+<h1>包 {{.Name}}</h1>
+<p><code>import "{{.ImportPath}}"</code></p>
+<p>{{range .Files}}<code>{{.}}</code> {{end}}</p>
+{{with .Error}}<pre class="error">{{.}}</pre>{{end}}
+<div id="pkg-overview">{{template "doc" .Overview}}</div>
+{{range .Sections}}
+<h2 id="{{.ID}}">{{.Title}}</h2>
+{{range .Decls}}
+{{if .Heading}}<h3 id="{{.ID}}"{{if .Sub}} class="sub"{{end}}>{{.Heading}}</h3>{{end}}
+<pre>{{.Code}}</pre>
+{{template "doc" .}}
+{{end}}
+{{end}}
+</div></div>
+<script>
+(function poll(v) {
+	var x = new XMLHttpRequest();
+	x.open("GET", "/-/reload?v=" + v);
+	x.onload = function() {
+		if (x.status == 200 && x.responseText != v) {
+			location.reload();
+		} else {
+			poll(v);
+		}
+	};
+	x.onerror = function() { setTimeout(function() { poll(v); }, 1000); };
+	x.send();
+})("{{.Version}}");
+</script>
+</body>
+</html>
+{{define "doc"}}
+{{if eq .Mode "both"}}<table class="bilingual"><tr><td lang="en">{{.English}}</td><td lang="zh-CN"{{if .Fuzzy}} class="fuzzy"{{end}}>{{.Chinese}}</td></tr></table>
+{{else if eq .Mode "en"}}<div lang="en">{{if .English}}{{.English}}{{else}}{{.Chinese}}{{end}}</div>
+{{else if .Untransl}}<div lang="en" class="untranslated">{{.English}}</div>
+{{else}}<div lang="zh-CN"{{if .Fuzzy}} class="fuzzy"{{end}}>{{.Chinese}}</div>
+{{end}}{{end}}
+`))
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+<title>包 - Go 编程语言</title>
+<link type="text/css" rel="stylesheet" href="/lib/godoc/style.css">
+</head>
+<body>
+<div id="topbar"><div class="container"><div id="heading"><a href="/">译文预览</a></div></div></div>
+<div id="page"><div class="container">
+<h1>包</h1>
+<ul>
+{{range .}}<li><a href="/pkg/{{.}}/">{{.}}</a></li>
+{{end}}</ul>
+</div></div>
+</body>
+</html>
+`))
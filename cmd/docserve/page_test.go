@@ -0,0 +1,130 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPageMode(t *testing.T) {
+	for _, tt := range []struct {
+		url, accept, want string
+	}{
+		{"/pkg/p/?lang=en", "zh-CN", modeEn},
+		{"/pkg/p/", "zh-CN,zh;q=0.8,en;q=0.6", modeZh},
+		{"/pkg/p/", "en-US,en;q=0.9,zh;q=0.8", modeEn},
+		{"/pkg/p/", "fr", modeBoth},
+		{"/pkg/p/?lang=xx", "", modeBoth},
+	} {
+		r, err := http.NewRequest("GET", tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Accept-Language", tt.accept)
+		if got := pageMode(r); got != tt.want {
+			t.Errorf("pageMode(%s, %q) = %s, want %s", tt.url, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestPackageRedirect(t *testing.T) {
+	for _, tt := range []struct{ url, want string }{
+		{"/pkg/strings", "/pkg/strings/"},
+		{"/pkg/strings?lang=en", "/pkg/strings/?lang=en"},
+	} {
+		r, err := http.NewRequest("GET", tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		new(server).ServeHTTP(w, r)
+		if got := w.Header().Get("Location"); w.Code != http.StatusMovedPermanently || got != tt.want {
+			t.Errorf("GET %s: %d to %q, want a redirect to %q", tt.url, w.Code, got, tt.want)
+		}
+	}
+}
+
+const testStub = `// +build ignore
+
+// Package p is a test.
+
+// p 包用于测试。
+package p
+
+// F does it.
+
+// F 完成它。
+func F()
+
+// T is a type.
+type T int
+
+// M is a method.
+
+// M 是一个方法。
+//zh:fuzzy
+func (T) M()
+`
+
+func TestLoadPage(t *testing.T) {
+	root, err := ioutil.TempDir("", "docserve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.Mkdir(filepath.Join(root, "p"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "p", "doc_zh_CN.go"), []byte(testStub), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := loadPage(root, "p", "linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headings []string
+	for _, s := range p.Sections {
+		for _, d := range s.Decls {
+			headings = append(headings, d.Heading)
+		}
+	}
+	if got, want := strings.Join(headings, ", "), "func F, type T, func (T) M"; got != want {
+		t.Errorf("headings = %s, want %s", got, want)
+	}
+
+	for _, tt := range []struct {
+		mode      string
+		want, not []string
+	}{
+		{modeZh, []string{"F 完成它。", `class="untranslated"><p>T is a type.`, `class="fuzzy"><p>M 是一个方法。`}, []string{"F does it."}},
+		{modeEn, []string{"F does it.", "T is a type."}, []string{"F 完成它。"}},
+		{modeBoth, []string{"F does it.", "F 完成它。", `class="bilingual"`}, nil},
+	} {
+		p.setMode(tt.mode)
+		var buf bytes.Buffer
+		if err := pageTemplate.Execute(&buf, p); err != nil {
+			t.Fatal(err)
+		}
+		html := buf.String()
+		for _, s := range tt.want {
+			if !strings.Contains(html, s) {
+				t.Errorf("%s: page lacks %s", tt.mode, s)
+			}
+		}
+		for _, s := range tt.not {
+			if strings.Contains(html, s) {
+				t.Errorf("%s: page has %s", tt.mode, s)
+			}
+		}
+	}
+}
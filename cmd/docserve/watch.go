@@ -0,0 +1,90 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// reloadTimeout bounds the time a reload request waits for a change.
+const reloadTimeout = 30 * time.Second
+
+// A watcher polls the stubs below a root for changes. It serves the
+// reload requests of the pages: a request for version v waits until the
+// version of the tree differs from v, and returns the new version.
+type watcher struct {
+	root string
+
+	mu      sync.Mutex
+	version int
+	sum     uint64
+	changed chan struct{} // closed at the next change
+}
+
+func newWatcher(root string) *watcher {
+	return &watcher{root: root, sum: scan(root), changed: make(chan struct{})}
+}
+
+// scan returns a checksum of the names, sizes and modification times of
+// the stubs below root.
+func scan(root string) uint64 {
+	h := fnv.New64a()
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && docstub.IsStubFile(path) {
+			fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return h.Sum64()
+}
+
+// run polls the tree every interval.
+func (w *watcher) run(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		sum := scan(w.root)
+		w.mu.Lock()
+		if sum != w.sum {
+			w.sum = sum
+			w.version++
+			close(w.changed)
+			w.changed = make(chan struct{})
+		}
+		w.mu.Unlock()
+	}
+}
+
+// current returns the current version, and a channel closed at the next
+// change.
+func (w *watcher) current() (int, <-chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.version, w.changed
+}
+
+func (w *watcher) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	version, changed := w.current()
+	if r.FormValue("v") == strconv.Itoa(version) {
+		select {
+		case <-changed:
+		case <-time.After(reloadTimeout):
+		case <-r.Context().Done():
+			return
+		}
+		version, _ = w.current()
+	}
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(rw, version)
+}
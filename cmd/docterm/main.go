@@ -0,0 +1,109 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docterm checks that the Chinese translations use the terms of the glossary.
+//
+// It reports the forbidden variants of the glossary terms found in the
+// Chinese blocks of the doc_zh_CN.go stubs, the translated parts of the HTML
+// documents and the Chinese comments of Go programs, such as the codewalks
+// and the blog examples, and exits with status 1 if there are any. With -w,
+// it replaces them by the approved renderings instead, spaced as docfmt
+// spaces them when they change from Chinese to Latin text or back.
+//
+// Usage:
+//
+//	docterm [flags] [path ...]
+//
+// The paths, files or directories, default to src, doc, blog, talks and tour.
+// The flags are:
+//
+//	-glossary file
+//		glossary file (default "glossary.json"), see package glossary
+//	-w
+//		replace the forbidden variants and print the names of the files changed
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/golang-china/golangdoc.translations/glossary"
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+var (
+	glossaryFile = flag.String("glossary", "glossary.json", "glossary `file`")
+	write        = flag.Bool("w", false, "replace the forbidden variants")
+)
+
+var defaultPaths = []string{"src", "doc", "blog", "talks", "tour"}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docterm [flags] [path ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docterm: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	g, err := glossary.Load(*glossaryFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+
+	found := false
+	err = zhtext.Walk(paths, func(f *zhtext.File, err error) error {
+		if err != nil {
+			return err
+		}
+		matches := check(g, f)
+		if len(matches) == 0 {
+			return nil
+		}
+		if !*write {
+			found = true
+			for _, m := range matches {
+				line, col := f.Position(m.Offset)
+				fmt.Printf("%s:%d:%d: %s should be %s (%s)\n", f.Name, line, col, m.Text, m.Fix(), m.Term.English)
+			}
+			return nil
+		}
+		src := glossary.Replace(string(f.Src), matches)
+		if err := ioutil.WriteFile(f.Name, []byte(src), 0644); err != nil {
+			return err
+		}
+		fmt.Println(f.Name)
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if found {
+		os.Exit(1)
+	}
+}
+
+// check returns the forbidden variants in the Chinese regions of f, with
+// offsets in f.
+func check(g *glossary.Glossary, f *zhtext.File) []glossary.Match {
+	var list []glossary.Match
+	for _, r := range f.Regions {
+		for _, m := range g.Find(f.Text(r)) {
+			m.Offset += r.Start
+			list = append(list, m)
+		}
+	}
+	return list
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Doctm looks up the translation memory of the repository: the translated
+// declarations of the src/**/doc_zh_CN.go stubs and the English and Chinese
+// paragraphs of the doc/zh_CN/*.html documents. Given English text, it prints
+// the existing translations of the most similar English texts, each with its
+// score, from 0 to 1, computed from the word edit distance of the two English
+// texts.
+//
+// Usage:
+//
+//	doctm [flags] [text]
+//	doctm [flags] -untranslated [importpath ...]
+//	doctm [flags] -http addr
+//
+// With text, or English read from standard input, doctm prints the
+// suggestions for it. With -untranslated, it prints suggestions for every
+// English only declaration of the packages. With -http, it serves the
+// memory over HTTP:
+//
+//	GET /suggest?q=text&n=3&min=0.6
+//
+// returns the suggestions for q as JSON; the text may also be sent as the body
+// of a POST request. The flags are:
+//
+//	-src dir
+//		root of the stub tree (default "src")
+//	-doc dir
+//		directory of the HTML documents (default "doc/zh_CN")
+//	-n count
+//		number of suggestions (default 3)
+//	-min score
+//		lowest score shown (default 0.6)
+//	-json
+//		print JSON instead of text
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/tm"
+)
+
+var (
+	srcDir       = flag.String("src", "src", "root of the stub tree")
+	docDir       = flag.String("doc", "doc/zh_CN", "directory of the HTML documents")
+	count        = flag.Int("n", 3, "number of suggestions")
+	minScore     = flag.Float64("min", 0.6, "lowest score shown")
+	jsonOut      = flag.Bool("json", false, "print JSON instead of text")
+	untranslated = flag.Bool("untranslated", false, "suggest translations for the English only declarations of the packages")
+	httpAddr     = flag.String("http", "", "serve the memory over HTTP on `addr`")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: doctm [flags] [text]\n")
+	fmt.Fprintf(os.Stderr, "       doctm [flags] -untranslated [importpath ...]\n")
+	fmt.Fprintf(os.Stderr, "       doctm [flags] -http addr\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("doctm: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	mem := tm.New()
+	if err := mem.AddStubs(*srcDir); err != nil {
+		log.Fatal(err)
+	}
+	if err := mem.AddHTML(*docDir); err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case *httpAddr != "":
+		log.Printf("%d entries; serving on %s", len(mem.Entries), *httpAddr)
+		log.Fatal(http.ListenAndServe(*httpAddr, &server{mem}))
+	case *untranslated:
+		if err := suggestUntranslated(os.Stdout, mem, flag.Args()); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		text := strings.Join(flag.Args(), " ")
+		if flag.NArg() == 0 {
+			b, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				log.Fatal(err)
+			}
+			text = string(b)
+		}
+		matches := mem.Lookup(text, *count, *minScore)
+		if *jsonOut {
+			writeJSON(os.Stdout, result{Query: text, Matches: matches})
+			return
+		}
+		writeMatches(os.Stdout, matches)
+	}
+}
+
+// A result is the suggestions for a text.
+type result struct {
+	Query   string      `json:"query"`
+	ID      string      `json:"id,omitempty"`
+	Matches []*tm.Match `json:"matches"`
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	w.Write(append(b, '\n'))
+}
+
+func writeMatches(w io.Writer, matches []*tm.Match) {
+	for _, m := range matches {
+		fmt.Fprintf(w, "%.2f\t%s", m.Score, m.Sources[0])
+		if n := len(m.Sources); n > 1 {
+			fmt.Fprintf(w, " (+%d)", n-1)
+		}
+		fmt.Fprintf(w, "\n\t%s\n", strings.Replace(m.Chinese, "\n", "\n\t", -1))
+	}
+}
+
+// suggestUntranslated prints the suggestions for the English only units of
+// the packages matching patterns.
+func suggestUntranslated(w io.Writer, mem *tm.Memory, patterns []string) error {
+	var results []result
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, *srcDir, func(pkg *docstub.Package, err error) error {
+		if !docstub.MatchPath(patterns, pkg.ImportPath) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ids := pkg.IDs()
+		for i, u := range pkg.Units() {
+			if u.English == "" || u.Translated() {
+				continue
+			}
+			matches := mem.Lookup(u.English, *count, *minScore)
+			if len(matches) == 0 {
+				continue
+			}
+			if *jsonOut {
+				results = append(results, result{Query: u.English, ID: ids[i], Matches: matches})
+				continue
+			}
+			fmt.Fprintf(w, "%s: %s\n", u.Pos, ids[i])
+			writeMatches(w, matches)
+			fmt.Fprintln(w)
+		}
+		return nil
+	})
+	if *jsonOut && err == nil {
+		writeJSON(w, results)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-china/golangdoc.translations/tm"
+)
+
+// maxQuery limits the size of a query sent in a request body.
+const maxQuery = 1 << 20
+
+// server serves the suggestions of a memory as JSON.
+type server struct {
+	mem *tm.Memory
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/suggest" {
+		http.NotFound(w, r)
+		return
+	}
+	// The parameters are read from the URL only: the body of a POST is
+	// the text itself, whatever its content type.
+	params := r.URL.Query()
+	q := params.Get("q")
+	if q == "" && r.Method == "POST" {
+		b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxQuery))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q = string(b)
+	}
+	if q == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+	n, min := *count, *minScore
+	var err error
+	if v := params.Get("n"); v != "" {
+		if n, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "bad n: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := params.Get("min"); v != "" {
+		if min, err = strconv.ParseFloat(v, 64); err != nil {
+			http.Error(w, "bad min: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	matches := s.mem.Lookup(q, n, min)
+	if matches == nil {
+		matches = []*tm.Match{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(result{Query: q, Matches: matches})
+}
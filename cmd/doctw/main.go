@@ -0,0 +1,276 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Doctw generates the Traditional Chinese (zh_TW) translations from the
+// Simplified Chinese (zh_CN) ones: a doc_zh_TW.go stub next to each
+// doc_zh_CN.go stub, and the doc/zh_TW and tour/zh_TW trees from doc/zh_CN
+// and tour/zh_CN.
+//
+// Usage:
+//
+//	doctw [flags] [dir ...]
+//
+// Only the Chinese text is converted, by package zhconv: code, identifiers
+// and the English text stay as they are, and the files without Chinese text,
+// such as images, are copied. The output is generated; do not edit it, but
+// fix the zh_CN text or the phrase table and run doctw again. Files of the
+// output whose zh_CN source is gone are removed.
+//
+// Doctw lists the simplified characters that it has no traditional form for
+// and left unchanged, such as 鲟, each once and most frequent first: add them
+// to the character map of package zhconv. It then lists the characters it
+// had to convert without knowing the word they belong to, such as 杆 in 斜杆,
+// grouped by their context and most frequent first. Adding the words to the
+// phrase table settles them.
+//
+// The flags are:
+//
+//	-phrases file
+//		phrase table (default "zh_TW.json"), see package zhconv
+//	-src dir
+//		root of the stub tree (default "src")
+//	-n
+//		only list the decisions; write nothing
+//	-v
+//		list every decision rather than one per context
+//
+// The arguments, by default doc/zh_CN and tour/zh_CN, are the trees to
+// convert besides the stubs.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/zhconv"
+)
+
+var (
+	phraseFile = flag.String("phrases", "zh_TW.json", "phrase table `file`")
+	srcDir     = flag.String("src", "src", "root of the stub tree")
+	dryRun     = flag.Bool("n", false, "only list the decisions; write nothing")
+	verbose    = flag.Bool("v", false, "list every decision")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: doctw [flags] [dir ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("doctw: ")
+	flag.Usage = usage
+	flag.Parse()
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"doc/zh_CN", "tour/zh_CN"}
+	}
+
+	conv, err := zhconv.Load(*phraseFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{conv: conv, written: make(map[string]bool)}
+	if err := g.stubs(*srcDir); err != nil {
+		log.Fatal(err)
+	}
+	for _, dir := range dirs {
+		if err := g.tree(dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+	g.report()
+	g.summary()
+}
+
+// A generator writes the zh_TW files.
+type generator struct {
+	conv      *zhconv.Converter
+	written   map[string]bool // output files, by name
+	changed   int             // output files written
+	decisions []decision
+}
+
+// A decision is a zhconv.Decision with its position.
+type decision struct {
+	zhconv.Decision
+	pos string
+}
+
+// stubs converts the stubs below root.
+func (g *generator) stubs(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			return nil
+		case docstub.IsStubFile(path):
+			return g.file(path, zhconv.TargetName(path), info.Mode())
+		case isTWStub(path) && !exists(filepath.Join(filepath.Dir(path), cnStubName(path))):
+			return g.remove(path)
+		}
+		return nil
+	})
+}
+
+// twPrefix is the prefix of the names of the zh_TW stubs.
+const twPrefix = "doc_zh_TW"
+
+// isTWStub reports whether path is a zh_TW stub.
+func isTWStub(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), twPrefix) && docstub.IsStubFile(cnStubName(path))
+}
+
+// cnStubName returns the name of the zh_CN stub of a zh_TW one.
+func cnStubName(path string) string {
+	return docstub.StubPrefix + strings.TrimPrefix(filepath.Base(path), twPrefix)
+}
+
+// tree converts the tree dir, and removes the files of its zh_TW tree that
+// are not generated from it.
+func (g *generator) tree(dir string) error {
+	out := zhconv.TargetName(dir)
+	if out == dir {
+		return fmt.Errorf("%s: not a zh_CN tree", dir)
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		return g.file(path, zhconv.TargetName(path), info.Mode())
+	})
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(out, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) || err == nil && (info.IsDir() || g.written[path]) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return g.remove(path)
+	})
+}
+
+// file converts the file src to dst.
+func (g *generator) file(src, dst string, mode os.FileMode) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	out := data
+	if zhconv.Convertible(src) {
+		var ds []zhconv.Decision
+		if out, ds, err = g.conv.File(src, data); err != nil {
+			return err
+		}
+		for _, d := range ds {
+			line := 1 + bytes.Count(data[:d.Offset], []byte("\n"))
+			g.decisions = append(g.decisions, decision{d, fmt.Sprintf("%s:%d", src, line)})
+		}
+	}
+	g.written[dst] = true
+	if old, err := ioutil.ReadFile(dst); err == nil && bytes.Equal(old, out) {
+		return nil
+	}
+	g.changed++
+	if *dryRun {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, out, mode.Perm())
+}
+
+func (g *generator) remove(path string) error {
+	g.changed++
+	if *dryRun {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// report prints the decisions, one line per context, most frequent first,
+// or every one with -v.
+func (g *generator) report() {
+	if *verbose {
+		for _, d := range g.decisions {
+			if d.Unmapped {
+				fmt.Printf("%s: %s in %s: no traditional form\n", d.pos, d.Char, d.Context)
+			} else {
+				fmt.Printf("%s: %s in %s: %s\n", d.pos, d.Char, d.Context, d.Default)
+			}
+		}
+		return
+	}
+	groups := make(map[string]*group)
+	var unmapped, list []*group
+	for _, d := range g.decisions {
+		key := d.Context
+		if d.Unmapped {
+			key = "unmapped " + d.Char
+		}
+		x := groups[key]
+		if x == nil {
+			x = &group{first: d}
+			groups[key] = x
+			if d.Unmapped {
+				unmapped = append(unmapped, x)
+			} else {
+				list = append(list, x)
+			}
+		}
+		x.count++
+	}
+	sort.Stable(byCount(unmapped))
+	for _, x := range unmapped {
+		fmt.Printf("%s: %s: no traditional form (%d times)\n", x.first.pos, x.first.Char, x.count)
+	}
+	sort.Stable(byCount(list))
+	for _, x := range list {
+		fmt.Printf("%s: %s in %s: %s (%d times)\n", x.first.pos, x.first.Char, x.first.Context, x.first.Default, x.count)
+	}
+}
+
+// A group is the decisions taken in one context, or the unmapped
+// occurrences of a character.
+type group struct {
+	first decision
+	count int
+}
+
+// byCount sorts groups most frequent first.
+type byCount []*group
+
+func (s byCount) Len() int           { return len(s) }
+func (s byCount) Less(i, j int) bool { return s[i].count > s[j].count }
+func (s byCount) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// summary prints the number of files written and of decisions.
+func (g *generator) summary() {
+	verb := "written"
+	if *dryRun {
+		verb = "to write"
+	}
+	log.Printf("%d files %s, %d decisions to make", g.changed, verb, len(g.decisions))
+}
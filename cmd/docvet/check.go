@@ -0,0 +1,265 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+// A problem is something wrong with a stub.
+type problem struct {
+	pos token.Position
+	msg string
+}
+
+type problems []problem
+
+func (p *problems) report(pos token.Position, format string, args ...interface{}) {
+	*p = append(*p, problem{pos, fmt.Sprintf(format, args...)})
+}
+
+// The build constraint every stub must carry, in the //go:build form and in
+// the // +build one, which gofmt adds to a //go:build line.
+var constraints = map[string]string{
+	"//go:build": "//go:build ignore",
+	"// +build":  "// +build ignore",
+}
+
+// checkBuild checks that the build constraint of the stub excludes it from
+// the builds of its package on its target: it must be "//go:build ignore",
+// "// +build ignore" or both, as gofmt writes them, and go/build must honor
+// it, which it does only for a constraint in the comments above the package
+// clause, followed by a blank line.
+func checkBuild(ctxt *build.Context, f *docstub.File) problems {
+	var probs problems
+	pos := f.Fset.Position(f.AST.Package)
+	pos.Column = 0
+	var lines []*ast.Comment
+	seen := make(map[string]bool)
+	for _, g := range f.AST.Comments {
+		if g.Pos() > f.AST.Package {
+			break
+		}
+		for _, c := range g.List {
+			for prefix, want := range constraints {
+				if !strings.HasPrefix(c.Text, prefix) {
+					continue
+				}
+				lines = append(lines, c)
+				switch text := strings.Join(strings.Fields(c.Text), " "); {
+				case seen[prefix]:
+					probs.report(f.Fset.Position(c.Pos()), "second build constraint %q", c.Text)
+				case text != want:
+					probs.report(f.Fset.Position(c.Pos()), "build constraint %q should be %q", c.Text, want)
+				}
+				seen[prefix] = true
+			}
+		}
+	}
+	if len(lines) == 0 {
+		probs.report(pos, "no build constraint: the stub is built into package %s", f.Package)
+		return probs
+	}
+
+	// go/build decides from the file itself.
+	c := *ctxt
+	c.OpenFile = func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(f.Src)), nil
+	}
+	dir, name := filepath.Split(f.Name)
+	match, err := c.MatchFile(dir, name)
+	switch {
+	case err != nil:
+		probs.report(pos, "%v", err)
+	case match:
+		probs.report(f.Fset.Position(lines[0].Pos()), "build constraint does not exclude the stub: it must be followed by a blank line")
+	}
+	return probs
+}
+
+// checkPackage checks that the package name of the stub is that of the real
+// package, if known, or else the last element of the import path; commands
+// are packages main.
+func checkPackage(f *docstub.File, importPath, realName string) problems {
+	var probs problems
+	want := realName
+	if want == "" {
+		want = path.Base(importPath)
+		if strings.HasPrefix(importPath, "cmd/") && f.Package == "main" {
+			want = "main"
+		}
+	}
+	if f.Package != want {
+		probs.report(f.Fset.Position(f.AST.Name.Pos()), "package %s in directory %s, want package %s", f.Package, importPath, want)
+	}
+	return probs
+}
+
+// checkComments checks that godoc attaches every comment of the stub to the
+// declaration it documents: a comment that is neither the doc comment of a
+// declaration nor the English comment paired with one is dropped by godoc.
+// The comments above the package clause and inside the declarations are not
+// checked.
+func checkComments(f *docstub.File) problems {
+	var probs problems
+	used := make(map[*ast.CommentGroup]bool)
+	for _, u := range f.Units {
+		used[u.EnglishDoc] = true
+		used[u.ChineseDoc] = true
+	}
+	inside := func(g *ast.CommentGroup) bool {
+		for _, d := range f.AST.Decls {
+			// A line comment after the declaration belongs to it.
+			end := f.Fset.Position(d.End()).Line
+			if d.Pos() <= g.Pos() && (g.End() <= d.End() || f.Fset.Position(g.Pos()).Line == end) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, g := range f.AST.Comments {
+		if used[g] || g.End() < f.AST.Package || inside(g) {
+			continue
+		}
+		what := "comment"
+		if docstub.HasHan(g.Text()) {
+			what = "Chinese comment"
+		}
+		probs.report(f.Fset.Position(g.Pos()), "%s not attached to a declaration: it must end on the line above the declaration", what)
+	}
+	return probs
+}
+
+// maxMissing is the number of missing identifiers of a unit listed.
+const maxMissing = 5
+
+// checkNames checks that the identifiers declared by the stub are declared
+// by the real package, as returned by loadNames.
+func checkNames(f *docstub.File, importPath string, names map[string]bool) problems {
+	var probs problems
+	for _, u := range f.Units {
+		var missing []string
+		switch u.Kind {
+		case docstub.PackageClause:
+			continue
+		case docstub.Method:
+			if !names[u.Name] && !names[u.Recv+".*"] {
+				missing = append(missing, u.Name)
+			}
+		default:
+			for _, name := range u.Names {
+				if name != "_" && !names[name] {
+					missing = append(missing, name)
+				}
+			}
+		}
+		if len(missing) > maxMissing {
+			missing = append(missing[:maxMissing], fmt.Sprintf("and %d more", len(missing)-maxMissing))
+		}
+		if len(missing) > 0 {
+			probs.report(u.Pos, "%s.%s not declared by the real package", importPath, strings.Join(missing, ", "))
+		}
+	}
+	return probs
+}
+
+// loadNames returns the name of the package importPath in the GOROOT of
+// ctxt, and the identifiers its files for the GOOS and GOARCH of ctxt
+// declare at package level, exported or not. Methods are named "T.M", and
+// include the methods promoted from the embedded types of the package, which
+// godoc lists too. The methods of an alias A are declared elsewhere; "A.*"
+// stands for them.
+func loadNames(ctxt *build.Context, importPath string) (string, map[string]bool, error) {
+	bp, err := ctxt.ImportDir(filepath.Join(ctxt.GOROOT, "src", filepath.FromSlash(importPath)), 0)
+	if err != nil {
+		return "", nil, err
+	}
+	names := make(map[string]bool)
+	methods := make(map[string][]string) // by type
+	embeds := make(map[string][]string)  // embedded types of the package, by type
+	fset := token.NewFileSet()
+	for _, name := range append(bp.GoFiles, bp.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, s := range d.Specs {
+					switch s := s.(type) {
+					case *ast.ValueSpec:
+						for _, id := range s.Names {
+							names[id.Name] = true
+						}
+					case *ast.TypeSpec:
+						names[s.Name.Name] = true
+						if s.Assign.IsValid() {
+							names[s.Name.Name+".*"] = true
+						}
+						if st, ok := s.Type.(*ast.StructType); ok {
+							for _, field := range st.Fields.List {
+								if len(field.Names) == 0 {
+									embeds[s.Name.Name] = append(embeds[s.Name.Name], recvName(field.Type))
+								}
+							}
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					names[d.Name.Name] = true
+				} else {
+					recv := recvName(d.Recv.List[0].Type)
+					methods[recv] = append(methods[recv], d.Name.Name)
+				}
+			}
+		}
+	}
+	var add func(typ, from string, seen map[string]bool)
+	add = func(typ, from string, seen map[string]bool) {
+		if seen[from] {
+			return
+		}
+		seen[from] = true
+		for _, m := range methods[from] {
+			names[typ+"."+m] = true
+		}
+		for _, e := range embeds[from] {
+			add(typ, e, seen)
+		}
+	}
+	for typ := range methods {
+		add(typ, typ, make(map[string]bool))
+	}
+	for typ := range embeds {
+		add(typ, typ, make(map[string]bool))
+	}
+	return bp.Name, names, nil
+}
+
+// recvName returns the type name of a receiver: T for T, *T, T[K] and *T[K].
+func recvName(x ast.Expr) string {
+	name := ""
+	ast.Inspect(x, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && name == "" {
+			name = id.Name
+		}
+		return name == ""
+	})
+	return name
+}
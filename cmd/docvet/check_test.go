@@ -0,0 +1,132 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/build"
+	"go/format"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+const testStub = `// +build ingore
+
+// Package p is a test.
+
+// p 包用于测试。
+package q
+
+// F does it.
+
+// F 完成它。
+
+func F()
+
+// G does it too.
+
+// G 也完成它。
+func G() // Trailing comment.
+
+// T is a type.
+
+// T 是一个类型。
+type T int
+
+// M is a method.
+
+// M 是一个方法。
+func (T) M()
+`
+
+func parse(t *testing.T, src string) *docstub.File {
+	f, err := docstub.ParseFile(token.NewFileSet(), "p/doc_zh_CN.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func messages(probs problems) []string {
+	var list []string
+	for _, p := range probs {
+		list = append(list, p.msg)
+	}
+	return list
+}
+
+func TestCheck(t *testing.T) {
+	f := parse(t, testStub)
+	for _, tt := range []struct {
+		name  string
+		probs problems
+		want  []string
+	}{
+		{"build", checkBuild(&build.Default, f), []string{
+			`build constraint "// +build ingore" should be "// +build ignore"`,
+		}},
+		{"package", checkPackage(f, "p", ""), []string{
+			"package q in directory p, want package p",
+		}},
+		{"comments", checkComments(f), []string{
+			"comment not attached to a declaration: it must end on the line above the declaration",
+			"Chinese comment not attached to a declaration: it must end on the line above the declaration",
+		}},
+		{"names", checkNames(f, "p", map[string]bool{"F": true, "T": true}), []string{
+			"p.G not declared by the real package",
+			"p.T.M not declared by the real package",
+		}},
+	} {
+		if got := messages(tt.probs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBuild(t *testing.T) {
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{"// +build ignore\n\n", nil},
+		{"", []string{"no build constraint: the stub is built into package p"}},
+		{"// +build ignore\n", []string{"build constraint does not exclude the stub: it must be followed by a blank line"}},
+		{"//go:build ignore\n\n", nil},
+		{"//go:build ignore\n// +build ignore\n\n", nil}, // as gofmt writes it
+		{"//go:build ignore\n// +build linux\n\n", []string{`build constraint "// +build linux" should be "// +build ignore"`}},
+		{"// +build ignore\n// +build ignore\n\n", []string{`second build constraint "// +build ignore"`}},
+	} {
+		f := parse(t, tt.header+"package p\n")
+		if got := messages(checkBuild(&build.Default, f)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestCheckBuildFormat checks that the stubs docgen writes pass, before and
+// after gofmt.
+func TestCheckBuildFormat(t *testing.T) {
+	src, err := docstub.Format(parse(t, "// +build ignore\n\n// Package p does things.\n\n// 包 p 做事。\npackage p\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(src), docstub.Header) {
+		t.Errorf("Format: no header in\n%s", src)
+	}
+	gofmt, err := format.Source(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gofmt, src) {
+		t.Errorf("gofmt changed the stub:\n%s\nto:\n%s", src, gofmt)
+	}
+	if got := messages(checkBuild(&build.Default, parse(t, string(src)))); got != nil {
+		t.Errorf("checkBuild: %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Docvet checks that the doc stubs of the translations tree are valid stubs,
+// which godoc shows and the go tool ignores.
+//
+// For each src/<importpath>/doc_zh_CN*.go stub it checks that
+//
+//   - the stub parses as Go;
+//   - its build constraint, "//go:build ignore", "// +build ignore" or
+//     both, as gofmt writes them, excludes it from builds;
+//   - its package name is that of the package in its directory;
+//   - godoc attaches each of its comments to a declaration, instead of
+//     dropping the comments separated from their declaration by a blank line;
+//   - each identifier it declares is declared by the real package of a
+//     GOROOT, for the GOOS and GOARCH named by the stub file.
+//
+// Usage:
+//
+//	docvet [flags] [importpath ...]
+//
+// An import path restricts the check to that package; a path ending in "/..."
+// also includes the packages below it. Docvet exits with status 1 if any
+// stub has a problem. The flags are:
+//
+//	-goroot dir
+//		GOROOT holding the real packages (default $GOROOT)
+//	-names
+//		check the declared identifiers against the real packages (default true)
+//	-src dir
+//		root of the stub tree (default "src")
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/build"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+)
+
+var (
+	goroot   = flag.String("goroot", build.Default.GOROOT, "GOROOT holding the real packages")
+	srcDir   = flag.String("src", "src", "root of the stub tree")
+	useNames = flag.Bool("names", true, "check the declared identifiers against the real packages")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: docvet [flags] [importpath ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+var exitCode = 0
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("docvet: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	patterns := flag.Args()
+	fset := token.NewFileSet()
+	err := docstub.Walk(fset, *srcDir, func(pkg *docstub.Package, _ error) error {
+		if !docstub.MatchPath(patterns, pkg.ImportPath) {
+			return nil
+		}
+		// Walk stops at the first stub of a directory that does not
+		// parse; parse them all here.
+		names, err := filepath.Glob(filepath.Join(pkg.Dir, docstub.StubPrefix+"*.go"))
+		if err != nil {
+			return err
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if docstub.IsStubFile(name) {
+				vet(fset, pkg.ImportPath, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(exitCode)
+}
+
+// context returns the build context of the real packages for goos and
+// goarch. Cgo is enabled so that the identifiers declared by cgo files
+// count.
+func context(goos, goarch string) *build.Context {
+	ctxt := build.Default
+	ctxt.GOROOT = *goroot
+	ctxt.CgoEnabled = true
+	if goos != "" {
+		ctxt.GOOS = goos
+	}
+	if goarch != "" {
+		ctxt.GOARCH = goarch
+	}
+	return &ctxt
+}
+
+func vet(fset *token.FileSet, importPath, filename string) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := docstub.ParseFile(fset, filename, src)
+	if err != nil {
+		fmt.Println(err)
+		exitCode = 1
+		return
+	}
+	ctxt := context(f.GOOS, f.GOARCH)
+	probs := checkBuild(ctxt, f)
+	probs = append(probs, checkComments(f)...)
+	realName := ""
+	if *useNames {
+		var names map[string]bool
+		realName, names, err = loadNames(ctxt, importPath)
+		if err != nil {
+			probs.report(token.Position{Filename: filename}, "cannot load the real package: %v", err)
+		} else {
+			probs = append(probs, checkNames(f, importPath, names)...)
+		}
+	}
+	probs = append(probs, checkPackage(f, importPath, realName)...)
+	sort.Stable(byPos(probs))
+	for _, p := range probs {
+		fmt.Printf("%s: %s\n", p.pos, p.msg)
+		exitCode = 1
+	}
+}
+
+type byPos problems
+
+func (s byPos) Len() int           { return len(s) }
+func (s byPos) Less(i, j int) bool { return s[i].pos.Offset < s[j].pos.Offset }
+func (s byPos) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,52 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// NormalizeSignature returns the token sequence of the declaration source
+// src, without comments, line breaks and alignment, so that signatures from
+// a stub and from the upstream source can be compared.
+func NormalizeSignature(src string) string {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, []byte(src), nil, 0)
+	var toks []string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue // automatically inserted
+		}
+		if lit != "" {
+			toks = append(toks, lit)
+		} else {
+			toks = append(toks, tok.String())
+		}
+	}
+	return strings.Join(toks, " ")
+}
+
+// SameText reports whether the doc texts a and b consist of the same words,
+// ignoring line wrapping and indentation.
+func SameText(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) != len(wb) {
+		return false
+	}
+	for i := range wa {
+		if wa[i] != wb[i] {
+			return false
+		}
+	}
+	return true
+}
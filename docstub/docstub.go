@@ -0,0 +1,403 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docstub parses the bilingual doc stubs (src/**/doc_zh_CN.go) of the
+// translations tree.
+//
+// Every declaration in a stub carries two comment blocks separated by a blank
+// line: the English text extracted from the Go source, followed by its
+// Chinese translation, which is the block godoc attaches to the declaration:
+//
+//	// Contains returns true if substr is within s.
+//
+//	// 判断字符串s是否包含子串substr。
+//	func Contains(s, substr string) bool
+//
+// The parser pairs the two blocks of the package clause, of every const, var
+// and type declaration (including the documented specs of grouped
+// declarations), of every function and of every method, and returns them as
+// translation units.
+package docstub
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind describes the kind of declaration a Unit documents.
+type Kind int
+
+const (
+	PackageClause Kind = iota // package clause
+	Const                     // const declaration or group
+	Var                       // var declaration or group
+	Type                      // type declaration
+	Func                      // function
+	Method                    // method
+)
+
+var kindNames = [...]string{
+	PackageClause: "package",
+	Const:         "const",
+	Var:           "var",
+	Type:          "type",
+	Func:          "func",
+	Method:        "method",
+}
+
+func (k Kind) String() string {
+	if 0 <= k && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Kinds lists all declaration kinds in the order the stubs use them.
+var Kinds = []Kind{PackageClause, Const, Var, Type, Func, Method}
+
+// MarkerPrefix starts the lines of a Chinese comment that carry
+// machine-readable markers, such as
+//
+//	//zh:untranslated
+//
+// Like //go: directives, gofmt keeps such lines and they are not part of the
+// doc text.
+const MarkerPrefix = "//zh:"
+
+// Markers.
+const (
+	Untranslated = "untranslated" // empty slot for the translation
+	Fuzzy        = "fuzzy"        // translation of an older English text, to be reviewed
+)
+
+// A Unit is a translation unit: one declaration of a stub together with its
+// English and Chinese doc comments.
+type Unit struct {
+	Kind  Kind
+	Name  string   // identifier; "T.M" for methods, the package name for the package clause
+	Names []string // all identifiers declared, in source order
+	Recv  string   // receiver base type name of a method
+
+	Signature string // source text of the declaration, without its doc comments
+	English   string // English doc text, as returned by ast.CommentGroup.Text
+	Chinese   string // Chinese doc text, as returned by ast.CommentGroup.Text
+
+	EnglishDoc *ast.CommentGroup // English comment block; nil if missing
+	ChineseDoc *ast.CommentGroup // Chinese comment block; nil if missing
+	Markers    []string          // markers of the Chinese block, without MarkerPrefix
+
+	// Node is the declaration: an *ast.File for the package clause, an
+	// *ast.GenDecl, *ast.ValueSpec or *ast.TypeSpec for const, var and
+	// type declarations, and an *ast.FuncDecl for functions and methods.
+	Node ast.Node
+	Pos  token.Position // position of the declaration
+}
+
+// Documented reports whether u has an English or a Chinese doc comment.
+func (u *Unit) Documented() bool {
+	return u.English != "" || u.Chinese != ""
+}
+
+// Translated reports whether u has a non-empty Chinese doc comment.
+func (u *Unit) Translated() bool {
+	return u.Chinese != ""
+}
+
+// Fuzzy reports whether the translation of u is flagged as fuzzy: it was
+// carried over from an older English text and needs review.
+func (u *Unit) Fuzzy() bool {
+	return u.HasMarker(Fuzzy)
+}
+
+// HasMarker reports whether the Chinese block of u carries the marker m.
+func (u *Unit) HasMarker(m string) bool {
+	for _, x := range u.Markers {
+		if x == m {
+			return true
+		}
+	}
+	return false
+}
+
+// A File is a parsed doc stub.
+type File struct {
+	Name    string // file name, as passed to ParseFile
+	Package string // package name
+	GOOS    string // target system of a doc_zh_CN_$GOOS.go file, or ""
+	GOARCH  string // target architecture of a doc_zh_CN_$GOARCH.go file, or ""
+	Units   []*Unit
+
+	Fset *token.FileSet
+	AST  *ast.File
+	Src  []byte
+}
+
+// ParseFile parses the stub named filename and returns its translation units
+// in source order. If src is nil the file is read from disk.
+func ParseFile(fset *token.FileSet, filename string, src []byte) (*File, error) {
+	if src == nil {
+		var err error
+		if src, err = ioutil.ReadFile(filename); err != nil {
+			return nil, err
+		}
+	}
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{
+		Name:    filename,
+		Package: f.Name.Name,
+		Fset:    fset,
+		AST:     f,
+		Src:     src,
+	}
+	file.GOOS, file.GOARCH = StubTarget(filename)
+	p := &stubParser{file: file}
+	p.parse()
+	return file, nil
+}
+
+// Lookup returns the first unit named name, or nil.
+func (f *File) Lookup(name string) *Unit {
+	for _, u := range f.Units {
+		if u.Name == name {
+			return u
+		}
+	}
+	return nil
+}
+
+type stubParser struct {
+	file *File
+}
+
+func (p *stubParser) parse() {
+	f := p.file.AST
+	p.add(&Unit{
+		Kind:      PackageClause,
+		Name:      f.Name.Name,
+		Names:     []string{f.Name.Name},
+		Signature: "package " + f.Name.Name,
+		Node:      f,
+	}, f.Package, f.Doc)
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			p.genDecl(d)
+		case *ast.FuncDecl:
+			p.funcDecl(d)
+		}
+	}
+}
+
+func (p *stubParser) genDecl(d *ast.GenDecl) {
+	var kind Kind
+	switch d.Tok {
+	case token.CONST:
+		kind = Const
+	case token.VAR:
+		kind = Var
+	case token.TYPE:
+		kind = Type
+	default:
+		return
+	}
+
+	if !d.Lparen.IsValid() {
+		// Single declaration: go/ast attaches the doc to the GenDecl.
+		names := specNames(d.Specs[0])
+		p.add(&Unit{
+			Kind:      kind,
+			Name:      names[0],
+			Names:     names,
+			Signature: p.source(d),
+			Node:      d,
+		}, d.Pos(), d.Doc)
+		return
+	}
+
+	// Grouped declaration. A const or var group is one unit; a type
+	// group is a list of independent types, so it only gets a unit of
+	// its own if it is documented as a whole.
+	var all []string
+	for _, s := range d.Specs {
+		all = append(all, specNames(s)...)
+	}
+	if len(all) > 0 && (kind != Type || d.Doc != nil) {
+		p.add(&Unit{
+			Kind:      kind,
+			Name:      all[0],
+			Names:     all,
+			Signature: p.source(d),
+			Node:      d,
+		}, d.Pos(), d.Doc)
+	}
+	for _, s := range d.Specs {
+		var doc *ast.CommentGroup
+		switch s := s.(type) {
+		case *ast.ValueSpec:
+			doc = s.Doc
+		case *ast.TypeSpec:
+			doc = s.Doc
+		}
+		if doc == nil && kind != Type {
+			continue
+		}
+		names := specNames(s)
+		p.add(&Unit{
+			Kind:      kind,
+			Name:      names[0],
+			Names:     names,
+			Signature: d.Tok.String() + " " + p.source(s),
+			Node:      s,
+		}, s.Pos(), doc)
+	}
+}
+
+func (p *stubParser) funcDecl(d *ast.FuncDecl) {
+	u := &Unit{
+		Kind:      Func,
+		Name:      d.Name.Name,
+		Names:     []string{d.Name.Name},
+		Signature: p.source(d),
+		Node:      d,
+	}
+	if d.Recv != nil && len(d.Recv.List) > 0 {
+		u.Kind = Method
+		u.Recv = RecvTypeName(d.Recv.List[0].Type)
+		u.Name = u.Recv + "." + d.Name.Name
+	}
+	p.add(u, d.Pos(), d.Doc)
+}
+
+// add completes u with the position pos and the comments paired with doc,
+// and appends it to the file's units.
+func (p *stubParser) add(u *Unit, pos token.Pos, doc *ast.CommentGroup) {
+	u.Pos = p.file.Fset.Position(pos)
+	if doc != nil {
+		if en := p.english(doc); en != nil {
+			u.EnglishDoc, u.ChineseDoc = en, doc
+		} else if HasHan(doc.Text()) {
+			u.ChineseDoc = doc
+		} else {
+			u.EnglishDoc = doc
+		}
+	}
+	if u.EnglishDoc != nil {
+		u.English = u.EnglishDoc.Text()
+	}
+	if u.ChineseDoc != nil {
+		u.Chinese, u.Markers = splitMarkers(u.ChineseDoc)
+	}
+	p.file.Units = append(p.file.Units, u)
+}
+
+// splitMarkers returns the doc text of g without its marker lines, and the
+// markers.
+func splitMarkers(g *ast.CommentGroup) (text string, markers []string) {
+	var list []*ast.Comment
+	for _, c := range g.List {
+		if strings.HasPrefix(c.Text, MarkerPrefix) {
+			markers = append(markers, strings.TrimSpace(c.Text[len(MarkerPrefix):]))
+			continue
+		}
+		list = append(list, c)
+	}
+	if len(list) == 0 {
+		return "", markers
+	}
+	return (&ast.CommentGroup{List: list}).Text(), markers
+}
+
+// english returns the English comment block paired with doc: the comment
+// group that starts a line at the same column as doc and ends exactly one
+// blank line above it. Build constraints and the copyright header never
+// pair.
+func (p *stubParser) english(doc *ast.CommentGroup) *ast.CommentGroup {
+	comments := p.file.AST.Comments
+	i := 0
+	for i < len(comments) && comments[i] != doc {
+		i++
+	}
+	if i == 0 || i == len(comments) {
+		return nil
+	}
+	prev := comments[i-1]
+
+	fset := p.file.Fset
+	docPos := fset.Position(doc.Pos())
+	prevPos := fset.Position(prev.Pos())
+	if fset.Position(prev.End()).Line != docPos.Line-2 || prevPos.Column != docPos.Column {
+		return nil
+	}
+	// The line between must be blank, not the spec of a group that
+	// the comment documents.
+	if len(bytes.TrimSpace(p.file.Src[fset.Position(prev.End()).Offset:docPos.Offset])) > 0 {
+		return nil
+	}
+	if !p.startsLine(prevPos) {
+		return nil
+	}
+	text := prev.Text()
+	if strings.HasPrefix(text, "+build") || strings.HasPrefix(text, "Copyright ") {
+		return nil
+	}
+	return prev
+}
+
+// startsLine reports whether only white space precedes pos on its line.
+func (p *stubParser) startsLine(pos token.Position) bool {
+	start := pos.Offset - (pos.Column - 1)
+	return len(bytes.TrimSpace(p.file.Src[start:pos.Offset])) == 0
+}
+
+func (p *stubParser) source(n ast.Node) string {
+	fset := p.file.Fset
+	return string(p.file.Src[fset.Position(n.Pos()).Offset:fset.Position(n.End()).Offset])
+}
+
+func specNames(s ast.Spec) []string {
+	var names []string
+	switch s := s.(type) {
+	case *ast.ValueSpec:
+		for _, n := range s.Names {
+			names = append(names, n.Name)
+		}
+	case *ast.TypeSpec:
+		names = append(names, s.Name.Name)
+	}
+	return names
+}
+
+// RecvTypeName returns the base type name of the receiver type expression x,
+// "T" for both T and *T.
+func RecvTypeName(x ast.Expr) string {
+	switch t := x.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return RecvTypeName(t.X)
+	case *ast.ParenExpr:
+		return RecvTypeName(t.X)
+	}
+	return ""
+}
+
+// HasHan reports whether s contains Han (Chinese) characters.
+func HasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
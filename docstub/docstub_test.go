@@ -0,0 +1,258 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+const testStub = `// Copyright The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ignore
+
+// Package p is a test.
+
+// p 包用于测试。
+package p
+
+// Mode flags.
+
+// 模式标志。
+const (
+	// A is the first.
+
+	// A 是第一个。
+	A Mode = iota
+	B // no doc
+)
+
+// ErrX is English only.
+var ErrX = errors.New("x")
+
+// 只有中文。
+func Only()
+
+// T is a type.
+
+// T 是一个类型。
+type T struct {
+	X int // field
+}
+
+// M is a method.
+
+// M 是一个方法。
+func (t *T) M(x int) error
+
+func (T) Undocumented()
+`
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile(token.NewFileSet(), "doc_zh_CN_windows.go", []byte(testStub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Package != "p" || f.GOOS != "windows" || f.GOARCH != "" {
+		t.Errorf("got package %q, target %q/%q", f.Package, f.GOOS, f.GOARCH)
+	}
+	type unit struct {
+		Kind      Kind
+		Name      string
+		Names     []string
+		English   string
+		Chinese   string
+		Signature string
+		Line      int
+	}
+	want := []unit{
+		{PackageClause, "p", []string{"p"}, "Package p is a test.\n", "p 包用于测试。\n", "package p", 10},
+		{Const, "A", []string{"A", "B"}, "Mode flags.\n", "模式标志。\n", "const (\n\t// A is the first.\n\n\t// A 是第一个。\n\tA Mode = iota\n\tB // no doc\n)", 15},
+		{Const, "A", []string{"A"}, "A is the first.\n", "A 是第一个。\n", "const A Mode = iota", 19},
+		{Var, "ErrX", []string{"ErrX"}, "ErrX is English only.\n", "", `var ErrX = errors.New("x")`, 24},
+		{Func, "Only", []string{"Only"}, "", "只有中文。\n", "func Only()", 27},
+		{Type, "T", []string{"T"}, "T is a type.\n", "T 是一个类型。\n", "type T struct {\n\tX int // field\n}", 32},
+		{Method, "T.M", []string{"M"}, "M is a method.\n", "M 是一个方法。\n", "func (t *T) M(x int) error", 39},
+		{Method, "T.Undocumented", []string{"Undocumented"}, "", "", "func (T) Undocumented()", 41},
+	}
+	var got []unit
+	for _, u := range f.Units {
+		got = append(got, unit{u.Kind, u.Name, u.Names, u.English, u.Chinese, u.Signature, u.Pos.Line})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("units:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseFileSpecs(t *testing.T) {
+	// Two English spec comments one line apart are not a pair.
+	const src = `// +build ignore
+
+package p
+
+var (
+	// ErrA is English.
+	ErrA = errors.New("a")
+	// ErrB is English too.
+	ErrB = errors.New("b")
+)
+`
+	f, err := ParseFile(token.NewFileSet(), "doc_zh_CN.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The package clause, the group and its two specs.
+	if len(f.Units) != 4 {
+		t.Fatalf("got %d units, want 4", len(f.Units))
+	}
+	for i, want := range []string{"ErrA is English.\n", "ErrB is English too.\n"} {
+		u := f.Units[2+i]
+		if u.English != want || u.Chinese != "" {
+			t.Errorf("%s: got English %q, Chinese %q; want English %q", u.Name, u.English, u.Chinese, want)
+		}
+	}
+}
+
+func TestStubTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		stub         bool
+		goos, goarch string
+	}{
+		{"doc_zh_CN.go", true, "", ""},
+		{"src/syscall/doc_zh_CN_windows.go", true, "windows", ""},
+		{"doc_zh_CN_amd64.go", true, "", "amd64"},
+		{"doc_zh_CN_linux_arm.go", true, "linux", "arm"},
+		{"doc_zh_CN_test.go", false, "", ""},
+		{"doc.go", false, "", ""},
+	}
+	for _, tt := range tests {
+		goos, goarch := StubTarget(tt.name)
+		if stub := IsStubFile(tt.name); stub != tt.stub || goos != tt.goos || goarch != tt.goarch {
+			t.Errorf("%s: got %v %q %q, want %v %q %q", tt.name, stub, goos, goarch, tt.stub, tt.goos, tt.goarch)
+		}
+		if tt.stub && StubName(goos, goarch) != tt.name[len(tt.name)-len(StubName(goos, goarch)):] {
+			t.Errorf("%s: StubName = %q", tt.name, StubName(goos, goarch))
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	fset := token.NewFileSet()
+	old, err := ParseFile(fset, "old.go", []byte(testStub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The const group as LoadUpstream returns it, with a spec comment
+	// that changed and a name, C, that the old group lacks.
+	group, err := parser.ParseFile(fset, "up.go", "package p\n\nconst (\n\t// A is the first one.\n\tA Mode = iota\n\tB\n\tC\n)\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decl := group.Decls[0]
+	f := &File{Package: "p", Fset: fset, Units: []*Unit{
+		{Kind: PackageClause, Name: "p", Names: []string{"p"}, Signature: "package p", English: "Package p is a test.\n"},
+		{Kind: Const, Name: "A", Names: []string{"A", "B", "C"}, Signature: FormatNode(fset, decl), English: "Mode flags.\n", Node: decl},
+		{Kind: Type, Name: "T", Names: []string{"T"}, Signature: "type T struct{}", English: "T is a new type.\n"},
+		{Kind: Method, Name: "T.N", Names: []string{"N"}, Recv: "T", Signature: "func (t *T) N()", English: "N is new.\n"},
+	}}
+	stats := Merge(f, old)
+	if stats.Kept != 1 || stats.Fuzzy != 3 || stats.New != 1 || len(stats.Obsolete) != 2 {
+		t.Errorf("got %d kept, %d fuzzy, %d new, %d obsolete; want 1, 3, 1, 2",
+			stats.Kept, stats.Fuzzy, stats.New, len(stats.Obsolete))
+	}
+
+	src, err := Format(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := ParseFile(token.NewFileSet(), "merged.go", src)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+	for i, want := range []struct {
+		chinese string
+		fuzzy   bool
+		slot    bool
+	}{
+		{"p 包用于测试。\n", false, false},
+		{"模式标志。\n", true, false}, // the group gained C
+		{"A 是第一个。\n", true, false},
+		{"T 是一个类型。\n", true, false},
+		{"", false, true},
+	} {
+		u := merged.Units[i]
+		if u.Chinese != want.chinese || u.Fuzzy() != want.fuzzy || u.HasMarker(Untranslated) != want.slot {
+			t.Errorf("%s: got %q fuzzy=%v untranslated=%v, want %q %v %v",
+				u.Name, u.Chinese, u.Fuzzy(), u.HasMarker(Untranslated), want.chinese, want.fuzzy, want.slot)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := ParseFile(fset, "p.go", []byte(testStub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edits []Edit
+	for _, u := range f.Units {
+		if u.Translated() {
+			edits = append(edits, u.MarkReviewed("chai2010", "go1.4"))
+		}
+	}
+	src := f.Apply(edits)
+	f, err = ParseFile(token.NewFileSet(), "p.go", src)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+	u := f.Lookup("T")
+	r := u.Review()
+	if r == nil || r.Reviewer != "chai2010" || r.GoVersion != "go1.4" || r.Hash != TextHash("T is a type.\n") {
+		t.Fatalf("review of T = %v", r)
+	}
+	if s, _ := u.ReviewState(""); s != UpToDate {
+		t.Errorf("state = %v, want reviewed", s)
+	}
+	if s, reason := u.ReviewState("go1.5"); s != Outdated || reason != "reviewed against go1.4" {
+		t.Errorf("state for go1.5 = %v %q, want outdated", s, reason)
+	}
+	if s, _ := f.Lookup("ErrX").ReviewState(""); s != NotTranslated {
+		t.Errorf("state of ErrX = %v, want not translated", s)
+	}
+
+	// The English text changed upstream.
+	u.English = "T is a struct type.\n"
+	if s, reason := u.ReviewState(""); s != Outdated || reason != "English text changed since the review" {
+		t.Errorf("state after an English change = %v %q, want outdated", s, reason)
+	}
+	// A new translation drops the review, a rewrapped one keeps it.
+	if e := u.Edit("T 是一个\n类型。\n", false); len(e.Markers) != 1 {
+		t.Errorf("rewrapped translation markers = %q, want the review", e.Markers)
+	}
+	if e := u.Edit("T 是一个结构体类型。\n", false); len(e.Markers) != 0 {
+		t.Errorf("new translation markers = %q, want none", e.Markers)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"go1.4", "go1.5", -1},
+		{"go1.4.2", "go1.4", +1},
+		{"go1.10", "go1.9", +1},
+		{"go1.5", "go1.5.0", 0},
+	} {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
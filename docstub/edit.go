@@ -0,0 +1,209 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"bytes"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IDs returns an identifier for each unit of pkg, in the order of
+// pkg.Units: "importpath.Name", such as "strings.Reader.Len", or the import
+// path alone for the package clause. A name declared more than once in the
+// package, for instance by a const group and one of its documented specs,
+// gets "#2", "#3" and so on appended from its second occurrence on.
+func (pkg *Package) IDs() []string {
+	var ids []string
+	seen := make(map[string]int)
+	for _, u := range pkg.Units() {
+		id := pkg.ImportPath
+		if u.Kind != PackageClause {
+			id += "." + u.Name
+		}
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			id += "#" + strconv.Itoa(n)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// SplitID splits a unit identifier as returned by IDs into the import path
+// and the name; the name is empty for the package clause.
+func SplitID(id string) (importPath, name string) {
+	i := strings.LastIndex(id, "/") + 1
+	if j := strings.Index(id[i:], "."); j >= 0 {
+		return id[:i+j], id[i+j+1:]
+	}
+	if j := strings.Index(id[i:], "#"); j >= 0 {
+		return id[:i+j], ""
+	}
+	return id, ""
+}
+
+// An Edit replaces the Chinese comment of a unit.
+type Edit struct {
+	Unit    *Unit
+	Chinese string   // new Chinese doc text; empty leaves an Untranslated slot
+	Markers []string // new markers
+}
+
+// unchanged reports whether e leaves its unit as it is.
+func (e *Edit) unchanged() bool {
+	u := e.Unit
+	if e.Chinese != u.Chinese || len(e.Markers) != len(u.Markers) {
+		return false
+	}
+	for i := range e.Markers {
+		if e.Markers[i] != u.Markers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the source of f with the edits applied. Only the Chinese
+// comments of the edited units change: edits that leave a unit as it is are
+// ignored, so that applying them yields the original source byte for byte.
+// A new Chinese comment is written with TextComment, followed by its
+// markers below an empty comment line, as gofmt writes them, after the
+// English comment of its unit.
+func (f *File) Apply(edits []Edit) []byte {
+	var patches []patch
+	for i := range edits {
+		e := &edits[i]
+		if e.unchanged() {
+			continue
+		}
+		u := e.Unit
+		markers := e.Markers
+		if e.Chinese == "" && u.English != "" && !contains(markers, Untranslated) {
+			markers = append([]string(nil), markers...)
+			markers = append(markers, Untranslated)
+		}
+		var lines []string
+		if e.Chinese != "" {
+			lines = strings.Split(strings.TrimSuffix(TextComment(e.Chinese), "\n"), "\n")
+			if len(markers) > 0 {
+				// gofmt separates the markers, which are
+				// directives to it, from the text.
+				lines = append(lines, "//")
+			}
+		}
+		for _, m := range markers {
+			lines = append(lines, MarkerPrefix+m)
+		}
+
+		switch {
+		case u.ChineseDoc != nil:
+			start := f.offset(u.ChineseDoc.Pos())
+			indent := f.indent(start)
+			p := patch{start: start, end: f.offset(u.ChineseDoc.End())}
+			if len(lines) == 0 {
+				// Remove the comment, with the blank line above
+				// it or the line break after it.
+				if u.EnglishDoc != nil {
+					p.start = f.offset(u.EnglishDoc.End())
+				} else {
+					p.start = start - len(indent)
+					p.end += 1 + len(indent)
+				}
+			} else {
+				p.text = strings.Join(lines, "\n"+indent)
+			}
+			patches = append(patches, p)
+		case len(lines) == 0:
+		case u.EnglishDoc != nil:
+			end := f.offset(u.EnglishDoc.End())
+			indent := f.indent(f.offset(u.EnglishDoc.Pos()))
+			patches = append(patches, patch{end, end, "\n\n" + indent + strings.Join(lines, "\n"+indent)})
+		default:
+			start := f.offset(u.Node.Pos())
+			if u.Kind == PackageClause {
+				start = f.offset(f.AST.Package)
+			}
+			indent := f.indent(start)
+			patches = append(patches, patch{start, start, strings.Join(lines, "\n"+indent) + "\n" + indent})
+		}
+	}
+
+	sort.Sort(byStart(patches))
+	var buf bytes.Buffer
+	last := 0
+	for _, p := range patches {
+		buf.Write(f.Src[last:p.start])
+		buf.WriteString(p.text)
+		last = p.end
+	}
+	buf.Write(f.Src[last:])
+	return buf.Bytes()
+}
+
+type patch struct {
+	start, end int // byte range of the source to replace
+	text       string
+}
+
+type byStart []patch
+
+func (s byStart) Len() int           { return len(s) }
+func (s byStart) Less(i, j int) bool { return s[i].start < s[j].start }
+func (s byStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (f *File) offset(pos token.Pos) int {
+	return f.Fset.Position(pos).Offset
+}
+
+// indent returns the white space between the start of the line and offset.
+func (f *File) indent(offset int) string {
+	start := bytes.LastIndexByte(f.Src[:offset], '\n') + 1
+	return string(f.Src[start:offset])
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Edit returns the edit that sets the Chinese text of u to chinese, flagged
+// fuzzy or not. The other markers of u are kept, except for the Untranslated
+// marker once there is a translation, and the Reviewed marker if the text
+// changes other than in its wrapping.
+func (u *Unit) Edit(chinese string, fuzzy bool) Edit {
+	if chinese = strings.TrimRight(chinese, "\n"); chinese != "" {
+		chinese += "\n"
+	}
+	fuzzy = fuzzy && chinese != ""
+	changed := strings.Join(strings.Fields(chinese), "") != strings.Join(strings.Fields(u.Chinese), "")
+	var markers []string
+	for _, m := range u.Markers {
+		if m == Fuzzy && !fuzzy || m == Untranslated && chinese != "" ||
+			changed && strings.HasPrefix(m, Reviewed+" ") {
+			continue
+		}
+		markers = append(markers, m)
+	}
+	if fuzzy && !contains(markers, Fuzzy) {
+		markers = append(markers, Fuzzy)
+	}
+	return Edit{Unit: u, Chinese: chinese, Markers: markers}
+}
+
+// Source returns the text a translation of u translates: its English
+// comment, or its signature if it has none.
+func (u *Unit) Source() string {
+	if u.English != "" {
+		return u.English
+	}
+	return u.Signature
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"strings"
+)
+
+// Header is the copyright header and build constraint every stub starts with.
+// The constraint is given in both forms, //go:build and // +build, as gofmt
+// writes it, so that gofmt leaves the stubs alone.
+const Header = `// Copyright The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+// +build ignore
+
+`
+
+// Format returns the gofmt-ed source of a stub holding the units of f, which
+// is typically loaded by LoadUpstream, in the house style: the Header, then
+// for each unit its English comment wrapped at LineWidth, a blank line, its
+// Chinese comment and its signature.
+//
+// A Chinese comment is written as it appears in the source if the unit has a
+// ChineseDoc, and built from the Chinese text otherwise. Documented units
+// without a translation get an empty slot for it, a comment holding only the
+// Untranslated marker. The markers of a unit follow its Chinese comment.
+func Format(f *File) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(Header)
+	for i, u := range f.Units {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeDoc(&buf, u)
+		buf.WriteString(u.Signature)
+		buf.WriteString("\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+func writeDoc(buf *bytes.Buffer, u *Unit) {
+	if u.English != "" {
+		buf.WriteString(CommentLines(u.English, LineWidth))
+		buf.WriteString("\n")
+	}
+	switch {
+	case u.ChineseDoc != nil:
+		buf.WriteString(commentSource(u.ChineseDoc))
+	case u.Chinese != "":
+		buf.WriteString(TextComment(u.Chinese))
+	case u.English != "" && !u.HasMarker(Untranslated):
+		buf.WriteString(MarkerPrefix + Untranslated + "\n")
+	}
+	for _, m := range u.Markers {
+		buf.WriteString(MarkerPrefix + m + "\n")
+	}
+}
+
+// commentSource returns the comments of g as they appear in the source, one
+// per line, without marker lines.
+func commentSource(g *ast.CommentGroup) string {
+	var buf bytes.Buffer
+	for _, c := range g.List {
+		if strings.HasPrefix(c.Text, MarkerPrefix) {
+			continue
+		}
+		buf.WriteString(c.Text)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// TextComment turns the doc text as returned by ast.CommentGroup.Text back
+// into // comment lines, without rewrapping. The result ends in a newline.
+func TextComment(text string) string {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case line == "":
+			buf.WriteString("//\n")
+		case line[0] == '\t':
+			buf.WriteString("//" + line + "\n")
+		default:
+			buf.WriteString("// " + line + "\n")
+		}
+	}
+	return buf.String()
+}
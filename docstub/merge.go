@@ -0,0 +1,210 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"bytes"
+	"go/ast"
+	"strings"
+)
+
+// MergeStats summarizes the result of Merge.
+type MergeStats struct {
+	Kept     int     // translations carried over unchanged
+	Fuzzy    int     // translations carried over and flagged Fuzzy
+	New      int     // documented units without a translation
+	Obsolete []*Unit // translated units of the old stub that were dropped
+}
+
+// Merge carries the Chinese comments of the old stub over to the units of f,
+// typically freshly loaded by LoadUpstream, the way gettext's msgmerge
+// updates a PO file:
+//
+// A unit of f takes the translation of the old unit of the same kind and
+// name. If their English texts differ, or if the const or var group only
+// shares some of its names with the old one, the translation is flagged with
+// the Fuzzy marker, to be reviewed. Markers of the old translation are kept.
+//
+// The documented specs of a const or var group take the translations of the
+// old specs of the same name the same way: their Chinese comments and markers
+// are inserted into the signature of the group, below the English comments.
+//
+// Translated units of old that match nothing in f are reported as obsolete.
+func Merge(f, old *File) *MergeStats {
+	stats := new(MergeStats)
+	byName := make(map[Kind]map[string]*Unit)
+	specs := make(map[Kind]map[string]*Unit)
+	used := make(map[*Unit]bool)
+	for _, u := range old.Units {
+		index := byName
+		if _, ok := u.Node.(*ast.ValueSpec); ok {
+			index = specs
+		}
+		m := index[u.Kind]
+		if m == nil {
+			m = make(map[string]*Unit)
+			index[u.Kind] = m
+		}
+		for _, name := range unitKeys(u) {
+			if m[name] == nil {
+				m[name] = u
+			}
+		}
+	}
+
+	for _, u := range f.Units {
+		if d, ok := u.Node.(*ast.GenDecl); ok && d.Lparen.IsValid() {
+			mergeSpecs(u, d, specs[u.Kind], used, stats)
+		}
+		if !u.Documented() {
+			continue
+		}
+		var prev *Unit
+		fuzzy := false
+		m := byName[u.Kind]
+		if prev = m[u.Name]; prev == nil || !prev.Translated() {
+			// A const or var group may have lost or gained
+			// names; fall back to any name it shares.
+			prev = nil
+			for _, name := range unitKeys(u) {
+				if p := m[name]; p != nil && p.Translated() {
+					prev, fuzzy = p, true
+					break
+				}
+			}
+		}
+		if prev == nil || !prev.Translated() {
+			stats.New++
+			continue
+		}
+		if !sameNames(unitKeys(u), unitKeys(prev)) {
+			fuzzy = true
+		}
+		used[prev] = true
+		u.Chinese = prev.Chinese
+		u.ChineseDoc = prev.ChineseDoc
+		u.Markers = append([]string(nil), prev.Markers...)
+		if fuzzy || !SameText(u.English, prev.English) {
+			if !u.HasMarker(Fuzzy) {
+				u.Markers = append(u.Markers, Fuzzy)
+			}
+		}
+		if u.HasMarker(Fuzzy) {
+			stats.Fuzzy++
+		} else {
+			stats.Kept++
+		}
+	}
+
+	for _, u := range old.Units {
+		if u.Translated() && !used[u] {
+			stats.Obsolete = append(stats.Obsolete, u)
+		}
+	}
+	return stats
+}
+
+// mergeSpecs carries the translations of the old specs over to the
+// documented specs of the group u, whose declaration is d, by inserting their
+// Chinese comments into the signature of u.
+func mergeSpecs(u *Unit, d *ast.GenDecl, old map[string]*Unit, used map[*Unit]bool, stats *MergeStats) {
+	lines := strings.SplitAfter(u.Signature, "\n")
+	var buf bytes.Buffer
+	i := 0 // lines before i are written
+	for _, s := range d.Specs {
+		vs, ok := s.(*ast.ValueSpec)
+		if !ok || vs.Doc == nil {
+			continue
+		}
+		var prev *Unit
+		for _, name := range specNames(s) {
+			if p := old[name]; p != nil && p.Translated() {
+				prev = p
+				break
+			}
+		}
+		if prev == nil {
+			continue
+		}
+		// The line of the spec, below its English comment.
+		j := i
+		for j < len(lines) && !isSpecLine(lines[j], vs.Names[0].Name) {
+			j++
+		}
+		if j == len(lines) {
+			continue
+		}
+		for _, line := range lines[i:j] {
+			buf.WriteString(line)
+		}
+		i = j
+
+		used[prev] = true
+		markers := append([]string(nil), prev.Markers...)
+		fuzzy := prev.HasMarker(Fuzzy)
+		if !fuzzy && !SameText(vs.Doc.Text(), prev.English) {
+			markers = append(markers, Fuzzy)
+			fuzzy = true
+		}
+		chinese := TextComment(prev.Chinese)
+		if prev.ChineseDoc != nil {
+			chinese = commentSource(prev.ChineseDoc)
+		}
+		buf.WriteString("\n")
+		for _, line := range strings.SplitAfter(chinese, "\n") {
+			if line != "" {
+				buf.WriteString("\t" + line)
+			}
+		}
+		for _, m := range markers {
+			buf.WriteString("\t" + MarkerPrefix + m + "\n")
+		}
+		if fuzzy {
+			stats.Fuzzy++
+		} else {
+			stats.Kept++
+		}
+	}
+	for _, line := range lines[i:] {
+		buf.WriteString(line)
+	}
+	u.Signature = buf.String()
+}
+
+// isSpecLine reports whether line starts the spec declaring name.
+func isSpecLine(line, name string) bool {
+	line = strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(line, name) {
+		return false
+	}
+	rest := line[len(name):]
+	return rest == "" || strings.IndexAny(rest[:1], " \t,=\n") == 0
+}
+
+// unitKeys returns the names under which u is looked up: its name for
+// methods and the package clause, all its names otherwise.
+func unitKeys(u *Unit) []string {
+	if u.Kind == Method || u.Kind == PackageClause {
+		return []string{u.Name}
+	}
+	return u.Names
+}
+
+// sameNames reports whether a and b hold the same names, in any order.
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	m := make(map[string]bool)
+	for _, name := range a {
+		m[name] = true
+	}
+	for _, name := range b {
+		if !m[name] {
+			return false
+		}
+	}
+	return true
+}
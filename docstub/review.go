@@ -0,0 +1,156 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Reviewed is the marker of a reviewed translation. It is followed by the
+// reviewer, the Go version of the English text that was reviewed, and the
+// hash of that text, as returned by TextHash:
+//
+//	//zh:reviewed chai2010 go1.5 3f786850
+//
+// A review lasts as long as the English text: once docgen -merge changes it,
+// the hash no longer matches and the review is outdated.
+const Reviewed = "reviewed"
+
+// A Review records the review of a translation.
+type Review struct {
+	Reviewer  string // such as a GitHub user name
+	GoVersion string // such as "go1.5"
+	Hash      string // TextHash of the reviewed English text
+}
+
+// String returns the marker of r, without MarkerPrefix.
+func (r *Review) String() string {
+	return Reviewed + " " + r.Reviewer + " " + r.GoVersion + " " + r.Hash
+}
+
+// TextHash returns a short hash of the doc text s that ignores line
+// wrapping and indentation, like SameText.
+func TextHash(s string) string {
+	h := sha1.Sum([]byte(strings.Join(strings.Fields(s), " ")))
+	return hex.EncodeToString(h[:4])
+}
+
+// Review returns the review of the translation of u, or nil if it has none.
+func (u *Unit) Review() *Review {
+	for _, m := range u.Markers {
+		f := strings.Fields(m)
+		if len(f) == 4 && f[0] == Reviewed {
+			return &Review{Reviewer: f[1], GoVersion: f[2], Hash: f[3]}
+		}
+	}
+	return nil
+}
+
+// ReviewState describes the review of a translation.
+type ReviewState int
+
+const (
+	NotTranslated ReviewState = iota // no translation to review
+	Unreviewed                       // translated, never reviewed
+	Outdated                         // reviewed, but the review no longer holds
+	UpToDate                         // reviewed
+)
+
+var reviewStateNames = [...]string{
+	NotTranslated: "not translated",
+	Unreviewed:    "unreviewed",
+	Outdated:      "outdated",
+	UpToDate:      "reviewed",
+}
+
+func (s ReviewState) String() string {
+	if 0 <= s && int(s) < len(reviewStateNames) {
+		return reviewStateNames[s]
+	}
+	return "ReviewState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// ReviewState returns the state of the review of u, and for an outdated
+// review the reason. A review is outdated if the translation is fuzzy, if
+// the English text changed since, or if goVersion is not empty and the review
+// is of an older Go version.
+func (u *Unit) ReviewState(goVersion string) (state ReviewState, reason string) {
+	if !u.Translated() {
+		return NotTranslated, ""
+	}
+	r := u.Review()
+	switch {
+	case r == nil:
+		return Unreviewed, ""
+	case u.Fuzzy():
+		return Outdated, "translation is fuzzy"
+	case r.Hash != TextHash(u.Source()):
+		return Outdated, "English text changed since the review"
+	case goVersion != "" && CompareVersions(r.GoVersion, goVersion) < 0:
+		return Outdated, "reviewed against " + r.GoVersion
+	}
+	return UpToDate, ""
+}
+
+// MarkReviewed returns the edit that records the review of the translation
+// of u by reviewer against goVersion. It replaces an older review and the
+// Fuzzy marker, which a review settles.
+func (u *Unit) MarkReviewed(reviewer, goVersion string) Edit {
+	r := &Review{Reviewer: reviewer, GoVersion: goVersion, Hash: TextHash(u.Source())}
+	var markers []string
+	for _, m := range u.Markers {
+		if m == Fuzzy || strings.HasPrefix(m, Reviewed+" ") {
+			continue
+		}
+		markers = append(markers, m)
+	}
+	markers = append(markers, r.String())
+	return Edit{Unit: u, Chinese: u.Chinese, Markers: markers}
+}
+
+// CompareVersions compares the Go versions a and b, such as "go1.4.2" and
+// "go1.5", and returns -1, 0 or +1. Versions that do not parse compare as
+// strings.
+func CompareVersions(a, b string) int {
+	va, oka := parseVersion(a)
+	vb, okb := parseVersion(b)
+	if !oka || !okb {
+		return strings.Compare(a, b)
+	}
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return +1
+		}
+	}
+	return 0
+}
+
+func parseVersion(s string) ([]int, bool) {
+	if !strings.HasPrefix(s, "go") {
+		return nil, false
+	}
+	var v []int
+	for _, f := range strings.Split(s[2:], ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, false
+		}
+		v = append(v, n)
+	}
+	return v, true
+}
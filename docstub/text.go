@@ -0,0 +1,106 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// LineWidth is the width at which the stubs wrap English comments.
+const LineWidth = 80
+
+// CommentLines formats the doc text as a // comment, the way the stubs
+// present godoc text: paragraphs are wrapped at width columns, indented
+// (preformatted) lines are kept as they are and indented by a tab, and
+// paragraphs are separated by an empty // line. The result ends in a newline.
+func CommentLines(text string, width int) string {
+	var buf bytes.Buffer
+	for i, b := range blocks(text) {
+		if i > 0 {
+			buf.WriteString("//\n")
+		}
+		if b.pre {
+			for _, line := range b.lines {
+				buf.WriteString("//\t" + line + "\n")
+			}
+			continue
+		}
+		for _, line := range wrap(strings.Fields(strings.Join(b.lines, " ")), width) {
+			buf.WriteString("// " + line + "\n")
+		}
+	}
+	return buf.String()
+}
+
+type block struct {
+	pre   bool
+	lines []string
+}
+
+// blocks splits text into paragraphs and preformatted blocks. The common
+// indentation of a preformatted block is removed.
+func blocks(text string) []block {
+	var (
+		out []block
+		cur *block
+	)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			cur = nil
+			continue
+		}
+		pre := line[0] == ' ' || line[0] == '\t'
+		if cur == nil || cur.pre != pre {
+			out = append(out, block{pre: pre})
+			cur = &out[len(out)-1]
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	for i := range out {
+		if out[i].pre {
+			unindent(out[i].lines)
+		}
+	}
+	return out
+}
+
+func unindent(lines []string) {
+	prefix := lines[0][:len(lines[0])-len(strings.TrimLeft(lines[0], " \t"))]
+	for _, line := range lines[1:] {
+		for !strings.HasPrefix(line, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, line := range lines {
+		lines[i] = line[len(prefix):]
+	}
+}
+
+// wrap fills words into lines of at most width runes; longer words get a
+// line of their own.
+func wrap(words []string, width int) []string {
+	var lines []string
+	line, n := "", 0
+	for _, w := range words {
+		wn := utf8.RuneCountInString(w)
+		if n > 0 && n+1+wn > width {
+			lines = append(lines, line)
+			line, n = "", 0
+		}
+		if n > 0 {
+			line += " "
+			n++
+		}
+		line += w
+		n += wn
+	}
+	if n > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
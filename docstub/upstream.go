@@ -0,0 +1,142 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+)
+
+// LoadUpstream loads the package importPath from the GOROOT of ctxt, for the
+// GOOS and GOARCH of ctxt, and returns its exported API as a File whose units
+// carry the English documentation only, in the order godoc presents them:
+// the package clause, constants, variables, functions, then each type
+// followed by its constants, variables, constructors and methods.
+//
+// The returned File has no AST and no source; the Node of each unit is the
+// declaration as filtered by go/doc.
+func LoadUpstream(ctxt *build.Context, fset *token.FileSet, importPath string) (*File, error) {
+	dir := filepath.Join(ctxt.GOROOT, "src", filepath.FromSlash(importPath))
+	bp, err := ctxt.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	astPkg := &ast.Package{Name: bp.Name, Files: make(map[string]*ast.File)}
+	names := append(append([]string(nil), bp.GoFiles...), bp.CgoFiles...)
+	for _, name := range names {
+		filename := filepath.Join(dir, name)
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		astPkg.Files[filename] = f
+	}
+	pkg := doc.New(astPkg, importPath, 0)
+
+	file := &File{
+		Name:    dir,
+		Package: pkg.Name,
+		GOOS:    ctxt.GOOS,
+		GOARCH:  ctxt.GOARCH,
+		Fset:    fset,
+	}
+	u := &upstream{file: file}
+	u.add(&Unit{
+		Kind:      PackageClause,
+		Name:      pkg.Name,
+		Names:     []string{pkg.Name},
+		Signature: "package " + pkg.Name,
+		English:   pkg.Doc,
+	}, token.NoPos)
+	u.values(Const, pkg.Consts)
+	u.values(Var, pkg.Vars)
+	u.funcs(pkg.Funcs)
+	for _, t := range pkg.Types {
+		for _, s := range t.Decl.Specs {
+			s.(*ast.TypeSpec).Doc = nil
+		}
+		u.decl(&Unit{
+			Kind:    Type,
+			Name:    t.Name,
+			Names:   []string{t.Name},
+			English: t.Doc,
+		}, t.Decl)
+		u.values(Const, t.Consts)
+		u.values(Var, t.Vars)
+		u.funcs(t.Funcs)
+		u.funcs(t.Methods)
+	}
+	return file, nil
+}
+
+type upstream struct {
+	file *File
+}
+
+func (u *upstream) add(unit *Unit, pos token.Pos) {
+	if pos.IsValid() {
+		unit.Pos = u.file.Fset.Position(pos)
+	}
+	u.file.Units = append(u.file.Units, unit)
+}
+
+// decl sets the signature and node of unit to decl, stripped of its doc
+// comment, and adds unit.
+func (u *upstream) decl(unit *Unit, decl ast.Node) {
+	switch d := decl.(type) {
+	case *ast.GenDecl:
+		d.Doc = nil
+	case *ast.FuncDecl:
+		d.Doc = nil
+		d.Body = nil
+	}
+	unit.Node = decl
+	unit.Signature = FormatNode(u.file.Fset, decl)
+	u.add(unit, decl.Pos())
+}
+
+func (u *upstream) values(kind Kind, values []*doc.Value) {
+	for _, v := range values {
+		u.decl(&Unit{
+			Kind:    kind,
+			Name:    v.Names[0],
+			Names:   v.Names,
+			English: v.Doc,
+		}, v.Decl)
+	}
+}
+
+func (u *upstream) funcs(funcs []*doc.Func) {
+	for _, f := range funcs {
+		unit := &Unit{
+			Kind:    Func,
+			Name:    f.Name,
+			Names:   []string{f.Name},
+			English: f.Doc,
+		}
+		if f.Recv != "" {
+			unit.Kind = Method
+			unit.Recv = RecvTypeName(f.Decl.Recv.List[0].Type)
+			unit.Name = unit.Recv + "." + f.Name
+		}
+		u.decl(unit, f.Decl)
+	}
+}
+
+// FormatNode returns node formatted the way gofmt would print it.
+func FormatNode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docstub
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StubPrefix is the common prefix of the stub file names.
+const StubPrefix = "doc_zh_CN"
+
+const (
+	goosList   = "android darwin dragonfly freebsd linux nacl netbsd openbsd plan9 solaris windows "
+	goarchList = "386 amd64 amd64p32 arm armbe arm64 arm64be ppc64 ppc64le mips mipsle mips64 mips64le mips64p32 mips64p32le ppc s390 s390x sparc sparc64 "
+)
+
+func isGOOS(s string) bool   { return s != "" && strings.Contains(goosList, s+" ") }
+func isGOARCH(s string) bool { return s != "" && strings.Contains(goarchList, s+" ") }
+
+// IsStubFile reports whether the base name of filename is that of a doc stub:
+// doc_zh_CN.go, or doc_zh_CN_$GOOS.go, doc_zh_CN_$GOARCH.go or
+// doc_zh_CN_$GOOS_$GOARCH.go for platform specific stubs.
+func IsStubFile(filename string) bool {
+	name := filepath.Base(filename)
+	if !strings.HasPrefix(name, StubPrefix) || !strings.HasSuffix(name, ".go") {
+		return false
+	}
+	rest := strings.TrimSuffix(name[len(StubPrefix):], ".go")
+	if rest == "" {
+		return true
+	}
+	goos, goarch := StubTarget(filename)
+	return goos != "" || goarch != ""
+}
+
+// StubTarget returns the GOOS and GOARCH encoded in the name of a platform
+// specific stub, such as "windows" for doc_zh_CN_windows.go. Both are empty
+// for the generic doc_zh_CN.go.
+func StubTarget(filename string) (goos, goarch string) {
+	name := filepath.Base(filename)
+	if !strings.HasPrefix(name, StubPrefix+"_") {
+		return "", ""
+	}
+	parts := strings.Split(strings.TrimSuffix(name[len(StubPrefix)+1:], ".go"), "_")
+	switch {
+	case len(parts) == 1 && isGOOS(parts[0]):
+		return parts[0], ""
+	case len(parts) == 1 && isGOARCH(parts[0]):
+		return "", parts[0]
+	case len(parts) == 2 && isGOOS(parts[0]) && isGOARCH(parts[1]):
+		return parts[0], parts[1]
+	}
+	return "", ""
+}
+
+// StubName returns the stub file name for the given target; the inverse of
+// StubTarget.
+func StubName(goos, goarch string) string {
+	name := StubPrefix
+	if goos != "" {
+		name += "_" + goos
+	}
+	if goarch != "" {
+		name += "_" + goarch
+	}
+	return name + ".go"
+}
+
+// A Package holds the stubs of one directory of the translations tree.
+type Package struct {
+	ImportPath string // import path, relative to the tree root
+	Dir        string // directory holding the stubs
+	Files      []*File
+}
+
+// Units returns the translation units of all files of pkg.
+func (pkg *Package) Units() []*Unit {
+	var units []*Unit
+	for _, f := range pkg.Files {
+		units = append(units, f.Units...)
+	}
+	return units
+}
+
+// ParseDir parses all stub files in dir, generic stub first. It returns the
+// files parsed so far and the first error encountered.
+func ParseDir(fset *token.FileSet, dir string) ([]*File, error) {
+	names, err := stubNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*File
+	for _, name := range names {
+		f, err := ParseFile(fset, filepath.Join(dir, name), nil)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func stubNames(dir string) ([]string, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+	all, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range all {
+		if IsStubFile(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names) // doc_zh_CN.go sorts before doc_zh_CN_*.go
+	return names, nil
+}
+
+// WalkFunc is the type of the function called by Walk for each directory
+// containing stubs. If the stubs failed to parse, err is the parse error and
+// pkg holds the files parsed successfully. If WalkFunc returns an error, Walk
+// stops and returns it.
+type WalkFunc func(pkg *Package, err error) error
+
+// Walk walks the tree rooted at root, typically the src directory of the
+// translations tree, in lexical order and calls fn for each directory
+// containing stubs. Import paths are relative to root.
+func Walk(fset *token.FileSet, root string, fn WalkFunc) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		names, err := stubNames(path)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		pkg := &Package{ImportPath: filepath.ToSlash(rel), Dir: path}
+		pkg.Files, err = ParseDir(fset, path)
+		return fn(pkg, err)
+	})
+}
+
+// MatchPath reports whether importPath matches one of patterns. A pattern is
+// an import path, or an import path followed by "/..." to also match the
+// packages below it. No patterns match everything.
+func MatchPath(patterns []string, importPath string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if p == importPath {
+			return true
+		}
+		if strings.HasSuffix(p, "/...") {
+			prefix := strings.TrimSuffix(p, "/...")
+			if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package glossary checks Chinese text against the terminology glossary of
+// the translations.
+//
+// A glossary is a JSON list of terms:
+//
+//	[
+//		{
+//			"english": "channel",
+//			"approved": ["通道"],
+//			"forbidden": ["信道"]
+//		}
+//	]
+//
+// Each term gives the approved Chinese renderings of an English term, the
+// first of which replaces the forbidden variants when fixing text. A
+// forbidden variant that is part of one of the term's "ignore" words, such as
+// "Go程" in "Go程序", is not a violation.
+package glossary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang-china/golangdoc.translations/zhfmt"
+)
+
+// A Term is an entry of the glossary.
+type Term struct {
+	English   string   `json:"english"`
+	Approved  []string `json:"approved"`
+	Forbidden []string `json:"forbidden"`
+	Ignore    []string `json:"ignore,omitempty"`
+}
+
+// A Glossary is a list of terms.
+type Glossary struct {
+	Terms []*Term
+}
+
+// Load reads a glossary file.
+func Load(filename string) (*Glossary, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return g, nil
+}
+
+// Parse parses the JSON form of a glossary and checks its terms.
+func Parse(data []byte) (*Glossary, error) {
+	g := new(Glossary)
+	if err := json.Unmarshal(data, &g.Terms); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]*Term)
+	for _, t := range g.Terms {
+		if len(t.Approved) == 0 {
+			return nil, fmt.Errorf("term %q has no approved rendering", t.English)
+		}
+		for _, f := range t.Forbidden {
+			if f == "" {
+				return nil, fmt.Errorf("term %q has an empty forbidden variant", t.English)
+			}
+			if u := seen[f]; u != nil {
+				return nil, fmt.Errorf("%q is forbidden by both %q and %q", f, u.English, t.English)
+			}
+			seen[f] = t
+			for _, a := range t.Approved {
+				if strings.Contains(a, f) {
+					return nil, fmt.Errorf("term %q: approved %q contains forbidden %q", t.English, a, f)
+				}
+			}
+		}
+	}
+	return g, nil
+}
+
+// A Match is a forbidden variant found in a text.
+type Match struct {
+	Offset int    // byte offset in the text
+	Text   string // the forbidden variant
+	Term   *Term
+}
+
+// Fix returns the approved rendering replacing m.
+func (m *Match) Fix() string { return m.Term.Approved[0] }
+
+// Find returns the forbidden variants in text, in order. Of overlapping
+// variants, the longest is reported.
+func (g *Glossary) Find(text string) []Match {
+	var all []Match
+	for _, t := range g.Terms {
+		for _, f := range t.Forbidden {
+			for i := 0; ; {
+				j := strings.Index(text[i:], f)
+				if j < 0 {
+					break
+				}
+				i += j
+				if !ignored(text, i, len(f), t.Ignore) {
+					all = append(all, Match{i, f, t})
+				}
+				i += len(f)
+			}
+		}
+	}
+	sort.Sort(byOffset(all))
+	var list []Match
+	end := 0
+	for _, m := range all {
+		if m.Offset >= end {
+			list = append(list, m)
+			end = m.Offset + len(m.Text)
+		}
+	}
+	return list
+}
+
+// ignored reports whether text[i:i+n] is part of one of the words.
+func ignored(text string, i, n int, words []string) bool {
+	for _, w := range words {
+		for k := strings.Index(w, text[i:i+n]); k >= 0; {
+			if start := i - k; start >= 0 && strings.HasPrefix(text[start:], w) {
+				return true
+			}
+			next := strings.Index(w[k+1:], text[i:i+n])
+			if next < 0 {
+				break
+			}
+			k += next + 1
+		}
+	}
+	return false
+}
+
+// byOffset sorts matches by offset, longest first.
+type byOffset []Match
+
+func (s byOffset) Len() int      { return len(s) }
+func (s byOffset) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byOffset) Less(i, j int) bool {
+	if s[i].Offset != s[j].Offset {
+		return s[i].Offset < s[j].Offset
+	}
+	return len(s[i].Text) > len(s[j].Text)
+}
+
+// Replace returns text with the matches, as returned by Find, replaced by
+// their approved renderings. Where a rendering starts or ends in another
+// script than the variant it replaces, the spaces around it are set as
+// zhfmt sets them, so that "一个Go程，" becomes "一个 goroutine，".
+func Replace(text string, matches []Match) string {
+	var buf []byte
+	last := 0
+	for k, m := range matches {
+		start, end := m.Offset, m.Offset+len(m.Text)
+		fix := m.Fix()
+		if changesScript(m.Text, fix) {
+			limit := len(text)
+			if k+1 < len(matches) {
+				limit = matches[k+1].Offset
+			}
+			// Respace from the rune before the spaces before
+			// the rendering to the rune after the spaces after it.
+			i := start
+			for i > last && text[i-1] == ' ' {
+				i--
+			}
+			_, n := utf8.DecodeLastRuneInString(text[last:i])
+			i -= n
+			j := end
+			for j < limit && text[j] == ' ' {
+				j++
+			}
+			_, n = utf8.DecodeRuneInString(text[j:limit])
+			j += n
+			fix = zhfmt.Spacing(text[i:start] + fix + text[end:j])
+			start, end = i, j
+		}
+		buf = append(buf, text[last:start]...)
+		buf = append(buf, fix...)
+		last = end
+	}
+	return string(append(buf, text[last:]...))
+}
+
+// changesScript reports whether the rendering fix starts or ends in another
+// script, Chinese or not, than the variant it replaces.
+func changesScript(variant, fix string) bool {
+	first := func(s string) bool {
+		r, _ := utf8.DecodeRuneInString(s)
+		return zhfmt.Han(r)
+	}
+	last := func(s string) bool {
+		r, _ := utf8.DecodeLastRuneInString(s)
+		return zhfmt.Han(r)
+	}
+	return first(variant) != first(fix) || last(variant) != last(fix)
+}
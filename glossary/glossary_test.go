@@ -0,0 +1,74 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glossary
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGlossary = `[
+	{"english": "goroutine", "approved": ["goroutine"], "forbidden": ["Go程", "Go例程"], "ignore": ["Go程序"]},
+	{"english": "channel", "approved": ["通道"], "forbidden": ["信道"]},
+	{"english": "routine", "approved": ["例程"], "forbidden": ["例行"]}
+]`
+
+func TestFind(t *testing.T) {
+	g, err := Parse([]byte(testGlossary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "启动一个Go程，通过信道与Go程序中的其它Go例程通信。"
+	var found []string
+	for _, m := range g.Find(text) {
+		if text[m.Offset:m.Offset+len(m.Text)] != m.Text {
+			t.Errorf("bad offset %d for %s", m.Offset, m.Text)
+		}
+		found = append(found, m.Text)
+	}
+	if got, want := strings.Join(found, " "), "Go程 信道 Go例程"; got != want {
+		t.Errorf("found %s, want %s", got, want)
+	}
+	if got, want := Replace(text, g.Find(text)), "启动一个 goroutine，通过通道与Go程序中的其它 goroutine 通信。"; got != want {
+		t.Errorf("Replace = %s, want %s", got, want)
+	}
+}
+
+func TestReplaceSpacing(t *testing.T) {
+	g, err := Parse([]byte(`[
+		{"english": "goroutine", "approved": ["goroutine"], "forbidden": ["Go程"]},
+		{"english": "closure", "approved": ["闭包"], "forbidden": ["closure"]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{"Go程", "goroutine"},
+		{"每个Go程都有栈", "每个 goroutine 都有栈"},
+		{"每个 Go程 都有栈", "每个 goroutine 都有栈"},
+		{"（Go程）", "（goroutine）"},
+		{"Go程、Go程", "goroutine、goroutine"},
+		{"返回一个 closure 。", "返回一个闭包。"},
+		{"a closure", "a 闭包"},
+	} {
+		if got := Replace(tt.text, g.Find(tt.text)); got != tt.want {
+			t.Errorf("Replace(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		`[{"english": "x", "forbidden": ["y"]}]`,
+		`[{"english": "x", "approved": ["a"], "forbidden": ["y"]}, {"english": "z", "approved": ["b"], "forbidden": ["y"]}]`,
+		`[{"english": "x", "approved": ["通道"], "forbidden": ["通"]}]`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%s) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gocomment pairs the English comments of the Go programs of the
+// translations, the examples of doc, blog, talks and tour, with their
+// Chinese translations.
+//
+// The programs keep the English comments next to the Chinese ones, the way
+// the stubs do, in one of three layouts: a doc comment followed by a blank
+// line and its translation,
+//
+//	// Poll executes an HTTP HEAD request for url.
+//
+//	// Poll 为 url 执行一个 HTTP HEAD 请求。
+//	func (r *Resource) Poll() string {
+//
+// a comment followed by its translation on the next line, or a trailing
+// comment followed by its translation, on the same line or on the line
+// above:
+//
+//	// Create our input and output channels.
+//	// 创建我们的输入和输出信道。
+//	pending, complete := make(chan *Resource), make(chan *Resource)
+//	numPollers = 2 // number of Poller goroutines to launch // Poller Go程的启动数
+//
+// Some programs, such as doc/zh_CN/codewalk/pig.go, keep instead a copy of
+// the English code in a /* */ comment next to the translated code; the
+// comments of the copy are paired with those of the code at the same place.
+//
+// The programs are only scanned, so they need not be valid Go. The license
+// header, the directives, such as //go:build lines, the cgo preamble and the
+// markers of the present tool, such as "// START OMIT", are not comments to
+// translate.
+package gocomment
+
+import (
+	"bytes"
+	"go/format"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+// Languages of comments.
+const (
+	English = "en"
+	Chinese = "zh"
+)
+
+// A Comment is a run of comment lines in one language: a comment group, or
+// the part of one in that language.
+type Comment struct {
+	Lang     string
+	Start    int    // byte offset of the first comment marker
+	End      int    // byte offset after the last comment
+	Line     int    // line of the first comment
+	EndLine  int    // line of the last comment
+	Trailing bool   // the comment follows code on its line
+	Text     string // the text, without the comment markers
+	Pair     *Comment
+
+	anchor int       // number of code tokens before the comment
+	orig   *original // the copy of the code holding the comment, if any
+}
+
+// A File is a Go program with its comments.
+type File struct {
+	Name     string
+	Src      []byte
+	Comments []*Comment // in order
+
+	originals []*original
+}
+
+// Translated returns the English comments with a translation.
+func (f *File) Translated() []*Comment { return f.filter(English, true) }
+
+// Untranslated returns the English comments without a translation.
+func (f *File) Untranslated() []*Comment { return f.filter(English, false) }
+
+// Orphans returns the Chinese comments without an English original.
+func (f *File) Orphans() []*Comment { return f.filter(Chinese, false) }
+
+func (f *File) filter(lang string, paired bool) []*Comment {
+	var list []*Comment
+	for _, c := range f.Comments {
+		if c.Lang == lang && (c.Pair != nil) == paired {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// A piece is a comment of the source, or the part of one in a language.
+type piece struct {
+	start, end    int
+	line, endLine int
+	trailing      bool // follows code on its line
+	code          bool // code since the previous comment
+	anchor        int  // number of code tokens before the comment
+	lang          string
+	text          string
+	orig          *original // the copy of the code holding the comment
+}
+
+// A tok is a code token of the source.
+type tok struct {
+	tok    token.Token
+	lit    string
+	offset int
+}
+
+// An original is the English original of translated code, kept in a
+// /* */ comment next to it, as in doc/zh_CN/codewalk/pig.go:
+//
+//	/*
+//	const (
+//		win = 100 // The winning score in a game of Pig
+//	)
+//	*/
+//
+//	const (
+//		win = 100 // 在一场Pig游戏中获胜的分数
+//	)
+//
+// The comments of the original are paired with those of the code at the
+// same place, that is after the same code tokens.
+type original struct {
+	start, end         int    // the /* */ comment
+	codeStart, codeEnd int    // the lines of the translated code
+	body               string // the original code
+}
+
+var (
+	directiveRx = regexp.MustCompile(`^//(go:|line |export |extern |\s*\+build)`)
+	// The markers of the present tool, such as "// START OMIT" or
+	// "// HLfetch", and of the test harness, such as "// cmpout".
+	markerRx  = regexp.MustCompile(`(^|\s)OMIT$|^HL\w*$`)
+	harnessRx = regexp.MustCompile(`^(run|compile|cmpout|errorcheck|skip|build|runoutput|rundir)$`)
+)
+
+// Parse finds the comments of the Go program src and pairs them.
+func Parse(name string, src []byte) *File {
+	f := &File{Name: name, Src: src}
+	toks, pieces, _ := scan(name, src)
+	var list []*piece
+	licenseEnd := -1 // last line of the license header
+	for i := 0; i < len(pieces); i++ {
+		p := pieces[i]
+		lit := string(src[p.start:p.end])
+		t := text(lit)
+		switch {
+		case directiveRx.MatchString(lit), markerRx.MatchString(t),
+			p.line == 1 && harnessRx.MatchString(t):
+			continue
+		case strings.HasPrefix(t, "Copyright "),
+			licenseEnd == p.line-1 && !p.code && !p.trailing:
+			licenseEnd = p.endLine
+			continue
+		case p.anchor+1 < len(toks) && toks[p.anchor].tok == token.IMPORT && toks[p.anchor+1].lit == `"C"`:
+			// The preamble of cgo.
+			continue
+		}
+		if o, inner := findOriginal(name, src, p, toks); o != nil {
+			f.originals = append(f.originals, o)
+			list = append(list, inner...)
+			continue
+		}
+		list = append(list, split(p, src)...)
+	}
+	f.Comments = merge(list)
+	pair(f.Comments, src)
+	pairOriginals(f.Comments)
+	return f
+}
+
+// scan returns the code tokens and the comments of src, and the number
+// of syntax errors.
+func scan(name string, src []byte) ([]tok, []*piece, int) {
+	var (
+		toks   []tok
+		pieces []*piece
+		errors int
+		s      scanner.Scanner
+	)
+	fset := token.NewFileSet()
+	file := fset.AddFile(name, fset.Base(), len(src))
+	s.Init(file, src, func(token.Position, string) { errors++ }, scanner.ScanComments)
+	codeLine := 0 // line of the last code token
+	code := false // code since the last comment
+	for {
+		pos, t, lit := s.Scan()
+		if t == token.EOF {
+			break
+		}
+		if t == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		line := file.Line(pos)
+		if t != token.COMMENT {
+			if lit == "" {
+				lit = t.String()
+			}
+			toks = append(toks, tok{t, lit, file.Offset(pos)})
+			codeLine = line + strings.Count(lit, "\n")
+			code = true
+			continue
+		}
+		start := file.Offset(pos)
+		pieces = append(pieces, &piece{
+			start:    start,
+			end:      start + len(lit),
+			line:     line,
+			endLine:  line + strings.Count(lit, "\n"),
+			trailing: codeLine == line,
+			code:     code,
+			anchor:   len(toks),
+		})
+		code = false
+	}
+	return toks, pieces, errors
+}
+
+// findOriginal returns the original, if the comment p is one, and its
+// comments.
+func findOriginal(name string, src []byte, p *piece, toks []tok) (*original, []*piece) {
+	lit := string(src[p.start:p.end])
+	if !strings.HasPrefix(lit, "/*") {
+		return nil, nil
+	}
+	// Scan the body of the comment in place, for the offsets and the
+	// lines, with the source before it blanked out.
+	body := make([]byte, p.end-2)
+	for i := range body {
+		switch {
+		case i >= p.start+2:
+			body[i] = src[i]
+		case src[i] == '\n':
+			body[i] = '\n'
+		default:
+			body[i] = ' '
+		}
+	}
+	inner, pieces, errors := scan(name, body)
+	if errors > 0 || len(inner) < 3 {
+		return nil, nil
+	}
+	// The translated code is the nearest run of the same tokens.
+	at := -1
+	for i := 0; i+len(inner) <= len(toks); i++ {
+		if match(toks[i:i+len(inner)], inner) && (at < 0 || distance(i, p.anchor, len(inner)) < distance(at, p.anchor, len(inner))) {
+			at = i
+		}
+	}
+	if at < 0 {
+		return nil, nil
+	}
+	last := toks[at+len(inner)-1]
+	o := &original{
+		start:     p.start,
+		end:       p.end,
+		codeStart: zhtext.LineStart(src, toks[at].offset),
+		codeEnd:   zhtext.LineEnd(src, last.offset+len(last.lit)),
+		body:      strings.TrimRight(strings.TrimPrefix(string(src[p.start+2:p.end-2]), "\n"), " \t"),
+	}
+	var list []*piece
+	for _, q := range pieces {
+		q.anchor += at
+		q.orig = o
+		if markerRx.MatchString(text(string(src[q.start:q.end]))) {
+			continue
+		}
+		list = append(list, split(q, src)...)
+	}
+	return o, list
+}
+
+func match(a, b []tok) bool {
+	for i := range a {
+		if a[i].tok != b[i].tok || a[i].lit != b[i].lit {
+			return false
+		}
+	}
+	return true
+}
+
+// distance returns the distance in tokens between the run of n tokens at i
+// and the comment at anchor.
+func distance(i, anchor, n int) int {
+	switch {
+	case anchor < i:
+		return i - anchor
+	case anchor > i+n:
+		return anchor - i - n
+	}
+	return 0
+}
+
+// split returns the comment p, classified, or its English and Chinese parts
+// if it is a "// English // 中文" comment.
+func split(p *piece, src []byte) []*piece {
+	lit := string(src[p.start:p.end])
+	if i := mixed(lit); i >= 0 {
+		zh := *p
+		p.end = p.start + len(strings.TrimRight(lit[:i], " \t"))
+		zh.start = p.start + i
+		return []*piece{classify(p, src), classify(&zh, src)}
+	}
+	return []*piece{classify(p, src)}
+}
+
+// mixed returns the offset of the Chinese part of a "// English // 中文"
+// comment, or -1.
+func mixed(lit string) int {
+	if !strings.HasPrefix(lit, "//") {
+		return -1
+	}
+	for i := 2; i < len(lit); i++ {
+		if strings.HasPrefix(lit[i:], "//") && (lit[i-1] == ' ' || lit[i-1] == '\t') {
+			if docstub.HasHan(lit[i:]) && !docstub.HasHan(lit[:i]) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var quotedRx = regexp.MustCompile("'[^']*'|\"[^\"]*\"|`[^`]*`")
+
+// classify sets the language and the text of p. The Chinese text quoted in
+// an English comment, as in "// Error: '世' has value 0x4e16", does not
+// count.
+func classify(p *piece, src []byte) *piece {
+	p.text = text(string(src[p.start:p.end]))
+	switch t := quotedRx.ReplaceAllString(p.text, ""); {
+	case strings.IndexFunc(t, isChinese) >= 0:
+		p.lang = Chinese
+	case strings.IndexFunc(t, unicode.IsLetter) >= 0:
+		p.lang = English
+	}
+	return p
+}
+
+// isChinese reports whether r is a Han character or a full-width
+// punctuation mark, such as the 。 of a translated "// true."
+func isChinese(r rune) bool {
+	return unicode.Is(unicode.Han, r) || 0x3000 <= r && r <= 0x303f || 0xff00 <= r && r <= 0xffef
+}
+
+// text returns the text of a comment, without its markers.
+func text(lit string) string {
+	if strings.HasPrefix(lit, "/*") {
+		return strings.TrimSpace(strings.TrimSuffix(lit[2:], "*/"))
+	}
+	return strings.TrimSpace(strings.TrimPrefix(lit, "//"))
+}
+
+// merge merges the pieces into comments: the pieces on consecutive lines,
+// without code between them, in the same language or without one, such as
+// the empty lines of a comment.
+func merge(pieces []*piece) []*Comment {
+	var list []*Comment
+	var last *Comment
+	for _, p := range pieces {
+		if last != nil && !p.code && !p.trailing && !last.Trailing && p.line == last.EndLine+1 &&
+			p.orig == last.orig && (p.lang == last.Lang || p.lang == "") {
+			last.End, last.EndLine = p.end, p.endLine
+			last.Text += "\n" + p.text
+			continue
+		}
+		if p.lang == "" {
+			last = nil
+			continue
+		}
+		last = &Comment{
+			Lang:     p.lang,
+			Start:    p.start,
+			End:      p.end,
+			Line:     p.line,
+			EndLine:  p.endLine,
+			Trailing: p.trailing,
+			Text:     p.text,
+			anchor:   p.anchor,
+			orig:     p.orig,
+		}
+		list = append(list, last)
+	}
+	return list
+}
+
+// pair pairs the neighboring comments in different languages: on the same
+// line, on consecutive lines, or separated by a blank line.
+func pair(list []*Comment, src []byte) {
+	for i := 1; i < len(list); i++ {
+		a, b := list[i-1], list[i]
+		if a.Pair != nil || a.Lang == b.Lang || a.orig != b.orig {
+			continue
+		}
+		between := src[a.End:b.Start]
+		ok := false
+		switch {
+		case a.Trailing:
+			// The English // 中文 form.
+			ok = b.Line == a.EndLine && len(bytes.TrimSpace(between)) == 0
+		case b.Trailing:
+			// A comment above the line of the translated trailing one.
+			ok = b.Line == a.EndLine+1
+		default:
+			ok = b.Line-a.EndLine <= 2 && len(bytes.TrimSpace(between)) == 0
+		}
+		if ok {
+			a.Pair, b.Pair = b, a
+		}
+	}
+}
+
+// pairOriginals pairs the comments of the originals with the Chinese
+// comments of the translated code at the same place.
+func pairOriginals(list []*Comment) {
+	for _, c := range list {
+		if c.orig == nil || c.Pair != nil || c.Lang != English {
+			continue
+		}
+		for _, zh := range list {
+			if zh.orig == nil && zh.Pair == nil && zh.Lang == Chinese &&
+				zh.anchor == c.anchor && zh.Trailing == c.Trailing &&
+				c.orig.codeStart <= zh.Start && zh.Start < c.orig.codeEnd {
+				c.Pair, zh.Pair = zh, c
+				break
+			}
+		}
+	}
+}
+
+// Only returns the program with its comments in one language, lang: the
+// English version drops all the Chinese comments, and the Chinese version
+// the English comments that have a translation, but keeps the untranslated
+// ones. The originals are dropped; in the English version, they replace the
+// translated code. The result is formatted, if it is valid Go, to align the
+// trailing comments again.
+func (f *File) Only(lang string) []byte {
+	var edits []edit
+	for _, o := range f.originals {
+		edits = append(edits, edit{zhtext.LineStart(f.Src, o.start), zhtext.LineEnd(f.Src, o.end), ""})
+		if lang == English {
+			edits = append(edits, edit{o.codeStart, o.codeEnd, o.body})
+		}
+	}
+	for _, c := range f.Comments {
+		if c.orig != nil || c.Lang == lang || lang == Chinese && c.Pair == nil {
+			continue
+		}
+		start, end := c.Start, c.End
+		if !c.Trailing {
+			// Remove the whole lines, and the blank line between the
+			// comment and its pair.
+			start = zhtext.LineStart(f.Src, start)
+			end = zhtext.LineEnd(f.Src, end)
+			if p := c.Pair; p != nil && p.Line == c.EndLine+2 {
+				end = zhtext.LineEnd(f.Src, end)
+			} else if p != nil && p.EndLine == c.Line-2 {
+				start = zhtext.LineStart(f.Src, start-1)
+			}
+		} else if p := c.Pair; p == nil || p.Line != c.Line {
+			// Remove the space before a trailing comment too.
+			for start > 0 && (f.Src[start-1] == ' ' || f.Src[start-1] == '\t') {
+				start--
+			}
+		} else if c.Start < p.Start {
+			// The English part of English // 中文.
+			end = p.Start
+		} else {
+			// The Chinese part, with the space before it.
+			start = p.End
+		}
+		edits = append(edits, edit{start, end, ""})
+	}
+	sort.Sort(byStart(edits))
+
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		if e.end <= last {
+			// Inside replaced code.
+			continue
+		}
+		if e.start < last {
+			e.start = last
+		}
+		buf.Write(f.Src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(f.Src[last:])
+	if out, err := format.Source(buf.Bytes()); err == nil {
+		return out
+	}
+	return buf.Bytes()
+}
+
+// An edit replaces the source between start and end with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+type byStart []edit
+
+func (s byStart) Len() int { return len(s) }
+func (s byStart) Less(i, j int) bool {
+	if s[i].start != s[j].start {
+		return s[i].start < s[j].start
+	}
+	return s[i].end > s[j].end
+}
+func (s byStart) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,216 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocomment
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testProgram = `// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+package main
+
+import "fmt"
+
+const (
+	numPollers = 2 // number of Poller goroutines to launch // Poller Go程的启动数
+	// 状态的更新频率
+	statusInterval = 10 * time.Second // how often to report status
+)
+
+// Poll executes an HTTP HEAD request for url
+// and returns the HTTP status string.
+
+// Poll 为 url 执行一个 HTTP HEAD 请求，
+// 并返回 HTTP 状态字符串。
+func Poll(url string) string {
+	// Create our input and output channels.
+	// 创建我们的输入和输出信道。
+	pending := make(chan string)
+
+	// Launch some Poller goroutines.
+	for i := 0; i < numPollers; i++ {
+		go Poller(pending)
+	}
+
+	// 剩余的请求。
+	return ""
+}
+`
+
+func TestParse(t *testing.T) {
+	f := Parse("urlpoll.go", []byte(testProgram))
+	var got []string
+	for _, c := range f.Comments {
+		s := fmt.Sprintf("%s %d-%d", c.Lang, c.Line, c.EndLine)
+		if c.Trailing {
+			s += " trailing"
+		}
+		if c.Pair != nil {
+			s += fmt.Sprintf(" pair %d", c.Pair.Line)
+		}
+		got = append(got, s+": "+c.Text)
+	}
+	want := []string{
+		"en 12-12 trailing pair 12: number of Poller goroutines to launch",
+		"zh 12-12 trailing pair 12: Poller Go程的启动数",
+		"zh 13-13 pair 14: 状态的更新频率",
+		"en 14-14 trailing pair 13: how often to report status",
+		"en 17-18 pair 20: Poll executes an HTTP HEAD request for url\nand returns the HTTP status string.",
+		"zh 20-21 pair 17: Poll 为 url 执行一个 HTTP HEAD 请求，\n并返回 HTTP 状态字符串。",
+		"en 23-23 pair 24: Create our input and output channels.",
+		"zh 24-24 pair 23: 创建我们的输入和输出信道。",
+		"en 27-27: Launch some Poller goroutines.",
+		"zh 32-32: 剩余的请求。",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("comments:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if n := len(f.Translated()); n != 4 {
+		t.Errorf("%d translated comments, want 4", n)
+	}
+	if u := f.Untranslated(); len(u) != 1 || u[0].Line != 27 {
+		t.Errorf("untranslated comments: %v, want line 27", u)
+	}
+	if o := f.Orphans(); len(o) != 1 || o[0].Line != 32 {
+		t.Errorf("orphans: %v, want line 32", o)
+	}
+}
+
+func TestOnly(t *testing.T) {
+	f := Parse("urlpoll.go", []byte(testProgram))
+	en := `// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+package main
+
+import "fmt"
+
+const (
+	numPollers     = 2                // number of Poller goroutines to launch
+	statusInterval = 10 * time.Second // how often to report status
+)
+
+// Poll executes an HTTP HEAD request for url
+// and returns the HTTP status string.
+func Poll(url string) string {
+	// Create our input and output channels.
+	pending := make(chan string)
+
+	// Launch some Poller goroutines.
+	for i := 0; i < numPollers; i++ {
+		go Poller(pending)
+	}
+
+	return ""
+}
+`
+	if got := string(f.Only(English)); got != en {
+		t.Errorf("English:\n%s\nwant:\n%s", got, en)
+	}
+	zh := `// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+package main
+
+import "fmt"
+
+const (
+	numPollers = 2 // Poller Go程的启动数
+	// 状态的更新频率
+	statusInterval = 10 * time.Second
+)
+
+// Poll 为 url 执行一个 HTTP HEAD 请求，
+// 并返回 HTTP 状态字符串。
+func Poll(url string) string {
+	// 创建我们的输入和输出信道。
+	pending := make(chan string)
+
+	// Launch some Poller goroutines.
+	for i := 0; i < numPollers; i++ {
+		go Poller(pending)
+	}
+
+	// 剩余的请求。
+	return ""
+}
+`
+	if got := string(f.Only(Chinese)); got != zh {
+		t.Errorf("Chinese:\n%s\nwant:\n%s", got, zh)
+	}
+}
+
+const testOriginal = `package main
+
+/*
+const (
+	win            = 100 // The winning score in a game of Pig
+	gamesPerSeries = 10  // The number of games per series to simulate
+)
+*/
+
+const (
+	win            = 100 // 在一场Pig游戏中获胜的分数
+	gamesPerSeries = 10  // 每次连续模拟游戏的数量
+)
+
+func main() {
+	var c Char = '世' // Error: '世' has value 0x4e16, too large.
+	// START OMIT
+	fmt.Println(c) // true。
+	// STOP OMIT
+}
+`
+
+func TestOriginal(t *testing.T) {
+	f := Parse("pig.go", []byte(testOriginal))
+	var got []string
+	for _, c := range f.Comments {
+		s := fmt.Sprintf("%s %d", c.Lang, c.Line)
+		if c.Pair != nil {
+			s += fmt.Sprintf(" pair %d", c.Pair.Line)
+		}
+		got = append(got, s)
+	}
+	want := "en 5 pair 11|en 6 pair 12|zh 11 pair 5|zh 12 pair 6|en 16|zh 18"
+	if strings.Join(got, "|") != want {
+		t.Errorf("comments = %s, want %s", strings.Join(got, "|"), want)
+	}
+
+	en := `package main
+
+const (
+	win            = 100 // The winning score in a game of Pig
+	gamesPerSeries = 10  // The number of games per series to simulate
+)
+
+func main() {
+	var c Char = '世' // Error: '世' has value 0x4e16, too large.
+	// START OMIT
+	fmt.Println(c)
+	// STOP OMIT
+}
+`
+	if got := string(f.Only(English)); got != en {
+		t.Errorf("English:\n%s\nwant:\n%s", got, en)
+	}
+	zh := strings.Replace(testOriginal, testOriginal[len("package main\n\n"):strings.Index(testOriginal, "*/\n\n")+4], "", 1)
+	if got := string(f.Only(Chinese)); got != zh {
+		t.Errorf("Chinese:\n%s\nwant:\n%s", got, zh)
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package htmldoc reads the bilingual HTML documents of doc/zh_CN.
+//
+// A translated document keeps the English original next to the translation:
+// each English paragraph, heading or code block is wrapped in a
+//
+//	<div class="english">
+//	<p>
+//	...
+//	</p>
+//	</div>
+//
+// and is followed by its Chinese counterpart:
+//
+//	<p>
+//	...
+//	</p>
+//
+// The scanner here only understands the top level of such a document, which
+// is all the translation tools need; it is not a general HTML parser.
+package htmldoc
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang-china/golangdoc.translations/zhfmt"
+)
+
+// An Element is a top-level piece of a document: an element with its
+// content, a comment, or a run of text between elements.
+type Element struct {
+	Tag    string // lower case tag name; "!--" for comments, "" for text
+	ID     string // value of the id attribute, if any
+	Class  string // value of the class attribute, if any
+	Src    string // the markup, from the start tag to the end tag
+	Offset int    // byte offset of Src in the document
+	Line   int    // line of the start tag
+}
+
+// Inner returns the markup between the start and the end tag of e.
+func (e *Element) Inner() string {
+	if e.Tag == "" || e.Tag == "!--" {
+		return e.Src
+	}
+	i := strings.Index(e.Src, ">")
+	j := strings.LastIndex(e.Src, "</")
+	if i < 0 || j <= i {
+		return ""
+	}
+	return e.Src[i+1 : j]
+}
+
+// Text returns the text of e, see Text.
+func (e *Element) Text() string { return Text(e.Src) }
+
+// IsEnglish reports whether e is a <div class="english"> block.
+func (e *Element) IsEnglish() bool { return e.Tag == "div" && e.Class == "english" }
+
+// A Pair is an English block and its translation.
+type Pair struct {
+	Div     *Element   // the <div class="english"> element
+	English []*Element // the elements inside Div
+	Chinese []*Element // the elements following Div
+}
+
+// Line returns the line of the English block.
+func (p *Pair) Line() int { return p.Div.Line }
+
+// EnglishText returns the text of the English elements.
+func (p *Pair) EnglishText() string { return joinText(p.English) }
+
+// ChineseText returns the text of the Chinese elements.
+func (p *Pair) ChineseText() string { return joinText(p.Chinese) }
+
+func joinText(elems []*Element) string {
+	var text []string
+	for _, e := range elems {
+		if t := e.Text(); t != "" {
+			text = append(text, t)
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
+// A Document is a parsed document.
+type Document struct {
+	Name     string
+	Src      []byte
+	Elements []*Element // top-level elements, without white space
+	Pairs    []*Pair
+}
+
+// Parse parses the document src. The name is only recorded.
+//
+// The translation of an English block is taken to be the elements that
+// follow it, as many as the block holds, stopping early at the next English
+// block. Comments and text between elements are not part of a pair. English
+// blocks nested in other elements, such as list items in a <ul>, are paired
+// with their siblings the same way.
+func Parse(name string, src []byte) *Document {
+	doc := &Document{Name: name, Src: src}
+	doc.Elements = scan(string(src), 0, 1)
+	doc.Pairs = pairs(doc.Elements)
+	return doc
+}
+
+// Walk calls fn for each element of the document, in document order. It
+// visits the elements inside an element when fn returns true for it.
+func (d *Document) Walk(fn func(e *Element) bool) { walk(d.Elements, fn) }
+
+func walk(elems []*Element, fn func(e *Element) bool) {
+	for _, e := range elems {
+		if fn(e) {
+			walk(e.Children(), fn)
+		}
+	}
+}
+
+// pairs returns the pairs in the sequence of sibling elements.
+func pairs(elems []*Element) []*Pair {
+	var ps []*Pair
+	for i, e := range elems {
+		if !e.IsEnglish() {
+			if e.Tag != "" && e.Tag != "!--" && !rawTags[e.Tag] && strings.Contains(e.Src, "english") {
+				ps = append(ps, pairs(e.Children())...)
+			}
+			continue
+		}
+		p := &Pair{Div: e}
+		for _, x := range e.Children() {
+			if x.Tag != "!--" && x.Tag != "" {
+				p.English = append(p.English, x)
+			}
+		}
+		for _, x := range elems[i+1:] {
+			if x.IsEnglish() || len(p.Chinese) == len(p.English) {
+				break
+			}
+			if x.Tag != "!--" && x.Tag != "" {
+				p.Chinese = append(p.Chinese, x)
+			}
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// Children returns the elements inside e: none for comments, text and the
+// content of script and style elements.
+func (e *Element) Children() []*Element {
+	if e.Tag == "" || e.Tag == "!--" || rawTags[e.Tag] || voidTags[e.Tag] {
+		return nil
+	}
+	start := strings.Index(e.Src, ">") + 1
+	return scan(e.Inner(), e.Offset+start, e.Line+strings.Count(e.Src[:start], "\n"))
+}
+
+// voidTags are the elements without an end tag.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTags are the elements whose content is not markup.
+var rawTags = map[string]bool{"script": true, "style": true}
+
+var (
+	startTag = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9]*)`)
+	attrRx   = regexp.MustCompile(`\s([a-zA-Z-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+)
+
+// scan splits src into top-level elements. Offsets and lines are counted
+// from offset and line.
+func scan(src string, offset, line int) []*Element {
+	var elems []*Element
+	pos := 0
+	for pos < len(src) {
+		// Skip white space.
+		rest := src[pos:]
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		line += strings.Count(rest[:len(rest)-len(trimmed)], "\n")
+		pos += len(rest) - len(trimmed)
+		if pos >= len(src) {
+			break
+		}
+		rest = src[pos:]
+		e := &Element{Offset: offset + pos, Line: line}
+		var end int
+		switch m := startTag.FindStringSubmatch(rest); {
+		case strings.HasPrefix(rest, "<!--"):
+			e.Tag = "!--"
+			end = strings.Index(rest, "-->")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += len("-->")
+			}
+		case m != nil:
+			e.Tag = strings.ToLower(m[1])
+			end = elementEnd(rest, e.Tag)
+			tag := rest[:tagEnd(rest)]
+			for _, a := range attrRx.FindAllStringSubmatch(tag, -1) {
+				v := html.UnescapeString(strings.Trim(a[2], `"'`))
+				switch strings.ToLower(a[1]) {
+				case "id":
+					e.ID = v
+				case "class":
+					e.Class = v
+				}
+			}
+		default:
+			// Text up to the next tag; a stray end tag is text too.
+			end = strings.Index(rest[1:], "<") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+		}
+		e.Src = rest[:end]
+		elems = append(elems, e)
+		line += strings.Count(e.Src, "\n")
+		pos += end
+	}
+	return elems
+}
+
+// tagEnd returns the length of the start tag at the beginning of s.
+func tagEnd(s string) int {
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// closedBy lists, for the elements whose end tag may be omitted, the start
+// tags that close them implicitly.
+var closedBy = map[string]map[string]bool{
+	"p": set("address", "article", "aside", "blockquote", "div", "dl", "fieldset",
+		"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr",
+		"menu", "nav", "ol", "p", "pre", "section", "table", "ul"),
+	"li": set("li"),
+	"dt": set("dt", "dd"),
+	"dd": set("dt", "dd"),
+}
+
+func set(names ...string) map[string]bool {
+	m := make(map[string]bool)
+	for _, name := range names {
+		m[name] = true
+	}
+	return m
+}
+
+var tagName = regexp.MustCompile(`^</?([a-z][a-z0-9]*)`)
+
+// elementEnd returns the length of the element tag that starts s, including
+// its end tag. End tags omitted as HTML allows for p, li, dt and dd are
+// inferred; an element that is never closed extends to the end of s.
+func elementEnd(s, tag string) int {
+	n := tagEnd(s)
+	if voidTags[tag] || strings.HasSuffix(s[:n], "/>") {
+		return n
+	}
+	stack := []string{tag} // open elements
+	lower := asciiLower(s)
+	for i := n; i < len(s); {
+		j := strings.IndexByte(lower[i:], '<')
+		if j < 0 {
+			break
+		}
+		i += j
+		if strings.HasPrefix(lower[i:], "<!--") {
+			k := strings.Index(lower[i:], "-->")
+			if k < 0 {
+				return len(s)
+			}
+			i += k + len("-->")
+			continue
+		}
+		m := tagName.FindStringSubmatch(lower[i:])
+		top := stack[len(stack)-1]
+		switch {
+		case m == nil:
+			// Not a tag.
+		case rawTags[top] && m[1] != top:
+			// Markup in script or style.
+		case lower[i+1] == '/':
+			k := len(stack) - 1
+			for k >= 0 && stack[k] != m[1] {
+				k--
+			}
+			if k < 0 {
+				// A stray end tag closes an element with an optional end
+				// tag, and is ignored otherwise.
+				if len(stack) == 1 && closedBy[tag] != nil {
+					return i
+				}
+				break
+			}
+			if stack = stack[:k]; len(stack) == 0 {
+				return i + tagEnd(s[i:])
+			}
+		default:
+			if closedBy[top][m[1]] {
+				if stack = stack[:len(stack)-1]; len(stack) == 0 {
+					return i
+				}
+			}
+			if !voidTags[m[1]] && !strings.HasSuffix(s[i:i+tagEnd(s[i:])], "/>") {
+				stack = append(stack, m[1])
+			}
+		}
+		i++
+	}
+	return len(s)
+}
+
+// asciiLower is strings.ToLower for ASCII letters only, which keeps the
+// byte offsets of s.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+var tagRx = regexp.MustCompile(`<[^>]*>`)
+
+// Text returns the text of the markup: tags and comments are removed,
+// entities unescaped and white space collapsed to single spaces. White space
+// between two Chinese characters, such as the line breaks of a wrapped
+// Chinese paragraph, is removed.
+func Text(markup string) string {
+	s := markup
+	for {
+		i := strings.Index(s, "<!--")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(s[i:], "-->")
+		if j < 0 {
+			s = s[:i]
+			break
+		}
+		s = s[:i] + s[i+j+len("-->"):]
+	}
+	s = html.UnescapeString(tagRx.ReplaceAllString(s, ""))
+	var buf bytes.Buffer
+	for i, f := range strings.Fields(s) {
+		if i > 0 && !(zhfmt.Wide(lastRune(buf.String())) && zhfmt.Wide(firstRune(f))) {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(f)
+	}
+	return buf.String()
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmldoc
+
+import "testing"
+
+const testDoc = `<!--{
+	"Title": "测试"
+}-->
+
+<div class="english">
+<h2 id="intro">Introduction</h2>
+</div>
+
+<h2 id="引言">引言</h2>
+
+<div class="english">
+<p>
+First <code>paragraph</code> &amp; more.
+</p>
+<pre>
+x := 1
+</pre>
+</div>
+
+<p>
+第一段
+中文。
+<p>
+x := 1
+</pre>
+
+<ul>
+<div class="english">
+<li>An item.
+</div>
+<li>一项。
+</ul>
+
+<div class="english">
+<p>
+Untranslated.
+</p>
+</div>
+`
+
+func TestParse(t *testing.T) {
+	doc := Parse("test.html", []byte(testDoc))
+	type elem struct {
+		tag  string
+		line int
+		text string
+	}
+	var want = []struct {
+		line             int
+		english, chinese []elem
+	}{
+		{5, []elem{{"h2", 6, "Introduction"}}, []elem{{"h2", 9, "引言"}}},
+		{11, []elem{{"p", 12, "First paragraph & more."}, {"pre", 15, "x := 1"}},
+			// The second <p> closes the first, the </pre> is stray.
+			[]elem{{"p", 20, "第一段中文。"}, {"p", 23, "x := 1"}}},
+		{28, []elem{{"li", 29, "An item."}}, []elem{{"li", 31, "一项。"}}},
+		{34, []elem{{"p", 35, "Untranslated."}}, nil},
+	}
+	if len(doc.Pairs) != len(want) {
+		t.Fatalf("got %d pairs, want %d", len(doc.Pairs), len(want))
+	}
+	check := func(i int, side string, got []*Element, want []elem) {
+		if len(got) != len(want) {
+			t.Errorf("pair %d: %d %s elements, want %d", i, len(got), side, len(want))
+			return
+		}
+		for j, e := range got {
+			if e.Tag != want[j].tag || e.Line != want[j].line || e.Text() != want[j].text {
+				t.Errorf("pair %d: %s element %d is <%s> at line %d %q, want <%s> at line %d %q",
+					i, side, j, e.Tag, e.Line, e.Text(), want[j].tag, want[j].line, want[j].text)
+			}
+			if string(doc.Src[e.Offset:e.Offset+len(e.Src)]) != e.Src {
+				t.Errorf("pair %d: %s element %d: bad offset", i, side, j)
+			}
+		}
+	}
+	for i, p := range doc.Pairs {
+		if p.Line() != want[i].line {
+			t.Errorf("pair %d at line %d, want %d", i, p.Line(), want[i].line)
+		}
+		check(i, "English", p.English, want[i].english)
+		check(i, "Chinese", p.Chinese, want[i].chinese)
+	}
+	if id := doc.Pairs[0].Chinese[0].ID; id != "引言" {
+		t.Errorf("id = %q, want 引言", id)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	doc := Parse("test.html", []byte(testDoc))
+	var ids []string
+	doc.Walk(func(e *Element) bool {
+		if e.ID != "" {
+			ids = append(ids, e.ID)
+		}
+		return e.Tag != "ul"
+	})
+	if len(ids) != 2 || ids[0] != "intro" || ids[1] != "引言" {
+		t.Errorf("ids = %q, want [intro 引言]", ids)
+	}
+	n := 0
+	doc.Walk(func(e *Element) bool {
+		if e.Tag == "li" {
+			n++
+		}
+		return true
+	})
+	if n != 2 {
+		t.Errorf("walked %d <li> elements, want 2", n)
+	}
+}
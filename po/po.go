@@ -0,0 +1,233 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package po reads and writes gettext PO files.
+//
+// Only what the translation tools of this repository need is supported:
+// singular messages with msgctxt, translator and extracted comments,
+// references and flags. Obsolete (#~) and previous (#|) entries are skipped
+// when reading.
+package po
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A Message is one entry of a PO file.
+type Message struct {
+	Comments   []string // translator comments (# text)
+	Extracted  []string // extracted comments (#. text)
+	References []string // references (#: file:line)
+	Flags      []string // flags (#, fuzzy)
+	Context    string   // msgctxt
+	ID         string   // msgid
+	Str        string   // msgstr
+}
+
+// HasFlag reports whether m carries the flag.
+func (m *Message) HasFlag(flag string) bool {
+	for _, f := range m.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Fuzzy reports whether m is flagged fuzzy.
+func (m *Message) Fuzzy() bool { return m.HasFlag("fuzzy") }
+
+// A File is a PO file: the header entry, the msgstr of the empty msgid,
+// followed by messages.
+type File struct {
+	Header   string
+	Messages []*Message
+}
+
+// Parse reads a PO file from r.
+func Parse(r io.Reader) (*File, error) {
+	p := &parser{s: bufio.NewScanner(r), f: new(File), m: new(Message)}
+	p.s.Buffer(nil, 1<<20)
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.f, nil
+}
+
+type parser struct {
+	s    *bufio.Scanner
+	line int
+	f    *File
+	m    *Message // message being read
+	str  *string  // field the continuation lines append to
+	has  bool     // m has msgid
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("po: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parse() error {
+	for p.s.Scan() {
+		p.line++
+		line := strings.TrimSpace(p.s.Text())
+		switch {
+		case line == "":
+			p.str = nil
+		case strings.HasPrefix(line, "#~"), strings.HasPrefix(line, "#|"):
+			p.str = nil
+		case strings.HasPrefix(line, "#"):
+			if p.has {
+				p.flush()
+			}
+			p.comment(line)
+		case strings.HasPrefix(line, `"`):
+			if p.str == nil {
+				return p.errorf("unexpected string")
+			}
+			s, err := unquote(line)
+			if err != nil {
+				return p.errorf("%v", err)
+			}
+			*p.str += s
+		default:
+			i := strings.IndexAny(line, " \t")
+			if i < 0 {
+				return p.errorf("missing string after %s", line)
+			}
+			keyword := line[:i]
+			s, err := unquote(strings.TrimSpace(line[i:]))
+			if err != nil {
+				return p.errorf("%v", err)
+			}
+			switch keyword {
+			case "msgctxt":
+				if p.has {
+					p.flush()
+				}
+				p.m.Context = s
+				p.str = &p.m.Context
+			case "msgid":
+				if p.has {
+					p.flush()
+				}
+				p.m.ID = s
+				p.str = &p.m.ID
+				p.has = true
+			case "msgstr":
+				p.m.Str = s
+				p.str = &p.m.Str
+			default:
+				return p.errorf("unsupported keyword %s", keyword)
+			}
+		}
+	}
+	if err := p.s.Err(); err != nil {
+		return err
+	}
+	if p.has {
+		p.flush()
+	}
+	return nil
+}
+
+func (p *parser) comment(line string) {
+	p.str = nil
+	kind, text := line[:1], strings.TrimSpace(line[1:])
+	if len(line) > 1 {
+		kind = line[:2]
+		text = strings.TrimSpace(line[2:])
+	}
+	switch kind {
+	case "#.":
+		p.m.Extracted = append(p.m.Extracted, text)
+	case "#:":
+		p.m.References = append(p.m.References, strings.Fields(text)...)
+	case "#,":
+		for _, flag := range strings.Split(text, ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				p.m.Flags = append(p.m.Flags, flag)
+			}
+		}
+	default:
+		p.m.Comments = append(p.m.Comments, strings.TrimSpace(line[1:]))
+	}
+}
+
+// flush ends the message being read.
+func (p *parser) flush() {
+	if p.m.ID == "" && p.m.Context == "" && p.f.Header == "" && len(p.f.Messages) == 0 {
+		p.f.Header = p.m.Str
+	} else {
+		p.f.Messages = append(p.f.Messages, p.m)
+	}
+	p.m = new(Message)
+	p.str = nil
+	p.has = false
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("malformed string %s", s)
+	}
+	return strconv.Unquote(s)
+}
+
+// Write writes f to w in the usual PO layout. Multi-line strings are written
+// one line per string literal.
+func (f *File) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	writeString(&buf, "msgid", "")
+	writeString(&buf, "msgstr", f.Header)
+	for _, m := range f.Messages {
+		buf.WriteString("\n")
+		for _, c := range m.Comments {
+			buf.WriteString(strings.TrimRight("# "+c, " ") + "\n")
+		}
+		for _, c := range m.Extracted {
+			buf.WriteString(strings.TrimRight("#. "+c, " ") + "\n")
+		}
+		for _, r := range m.References {
+			buf.WriteString("#: " + r + "\n")
+		}
+		if len(m.Flags) > 0 {
+			buf.WriteString("#, " + strings.Join(m.Flags, ", ") + "\n")
+		}
+		if m.Context != "" {
+			writeString(&buf, "msgctxt", m.Context)
+		}
+		writeString(&buf, "msgid", m.ID)
+		writeString(&buf, "msgstr", m.Str)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeString(buf *bytes.Buffer, keyword, s string) {
+	buf.WriteString(keyword + " ")
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > 1 {
+		buf.WriteString(`""` + "\n")
+	}
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	for _, line := range lines {
+		buf.WriteString(quote(line) + "\n")
+	}
+}
+
+var quoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func quote(s string) string {
+	return `"` + quoter.Replace(s) + `"`
+}
@@ -0,0 +1,318 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package presentdoc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+// A Code is a .code or .play directive, such as
+//
+//	.code -edit tutorial/1get.go /func main/,/^}/ HLget
+//
+// which shows the lines of a file selected by an address, highlighting the
+// lines marked with a "// HLget" comment.
+type Code struct {
+	Line  int      // line of the directive
+	Cmd   string   // "code" or "play"
+	Flags []string // such as -edit and -numbers
+	File  string   // the file, relative to the directory of the document
+	Addr  string   // the address, "" for the whole file
+	HL    string   // the highlight marker, such as "HLget", or ""
+}
+
+// Code returns the .code and .play directives of the document.
+func (d *Document) Code() []*Code {
+	var list []*Code
+	for _, b := range d.Blocks {
+		if b.IsText() {
+			continue
+		}
+		for i, line := range strings.SplitAfter(b.Src, "\n") {
+			if c := parseCode(strings.TrimSpace(line)); c != nil {
+				c.Line = b.Line + i
+				list = append(list, c)
+			}
+		}
+	}
+	return list
+}
+
+var hlRx = regexp.MustCompile(`\s(HL[a-zA-Z0-9_]*)$`)
+
+// parseCode parses a .code or .play directive, and returns nil for other
+// lines.
+func parseCode(line string) *Code {
+	c := new(Code)
+	switch {
+	case strings.HasPrefix(line, ".code "):
+		c.Cmd = "code"
+	case strings.HasPrefix(line, ".play "):
+		c.Cmd = "play"
+	default:
+		return nil
+	}
+	rest := strings.TrimSpace(line[len(c.Cmd)+1:])
+	if m := hlRx.FindStringSubmatch(rest); m != nil {
+		c.HL = m[1]
+		rest = strings.TrimSpace(rest[:len(rest)-len(m[0])])
+	}
+	for strings.HasPrefix(rest, "-") {
+		i := strings.IndexAny(rest, " \t")
+		if i < 0 {
+			i = len(rest)
+		}
+		c.Flags = append(c.Flags, rest[:i])
+		rest = strings.TrimSpace(rest[i:])
+	}
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		c.File, c.Addr = rest[:i], strings.TrimSpace(rest[i:])
+	} else {
+		c.File = rest
+	}
+	return c
+}
+
+// Select returns the lines of the file src that c shows, or an error if
+// its address does not resolve in src or the highlight marker is missing.
+func (c *Code) Select(src []byte) ([]byte, error) {
+	lo, hi := 0, len(src)
+	if c.Addr != "" {
+		var err error
+		if lo, hi, err = Address(src, c.Addr); err != nil {
+			return nil, err
+		}
+	}
+	text := src[lo:hi]
+	if c.HL != "" && !bytes.Contains(text, []byte("// "+c.HL)) {
+		return nil, fmt.Errorf("no %q line to highlight", "// "+c.HL)
+	}
+	return text, nil
+}
+
+// Address returns the byte range of src selected by the address addr, in
+// the syntax of the present tool, which is that of the acme editor:
+//
+//	n          line n, 0 being the start of the file
+//	#n         byte offset n
+//	$          the end of the file
+//	/regexp/   the lines of the next match of regexp, wrapping around
+//	a+b, a-b   b counted forward or backward from a; a lone + or - is a line
+//	a,b        from the start of a to the end of b; a missing a is the start
+//	           of the file and a missing b its end
+func Address(src []byte, addr string) (lo, hi int, err error) {
+	p := &addrParser{src: src, s: addr}
+	defer func() {
+		switch e := recover().(type) {
+		case nil:
+		case addrError:
+			err = fmt.Errorf("address %s: %s", addr, string(e))
+		default:
+			panic(e)
+		}
+	}()
+	lo, hi = 0, 0
+	if p.peek() != ',' {
+		lo, hi = p.simple(0, 0)
+	}
+	if p.peek() == ',' {
+		p.s = p.s[1:]
+		end := len(src)
+		if p.s != "" {
+			_, end = p.simple(hi, hi)
+		}
+		if end < lo {
+			p.errorf("end before start")
+		}
+		hi = end
+	}
+	if p.s != "" {
+		p.errorf("unexpected %q", p.s)
+	}
+	return lo, hi, nil
+}
+
+type addrError string
+
+type addrParser struct {
+	src []byte
+	s   string // rest of the address
+}
+
+func (p *addrParser) errorf(format string, args ...interface{}) {
+	panic(addrError(fmt.Sprintf(format, args...)))
+}
+
+func (p *addrParser) peek() byte {
+	if p.s == "" {
+		return 0
+	}
+	return p.s[0]
+}
+
+// simple evaluates a simple address, terms joined by + and -, from the
+// range lo, hi. Terms side by side are joined by +. A stray slash at the
+// end, as in /if.delay/-2/, is ignored.
+func (p *addrParser) simple(lo, hi int) (int, int) {
+	first := true
+	for {
+		dir := byte('+')
+		switch c := p.peek(); {
+		case c == 0 || c == ',':
+			return lo, hi
+		case p.s == "/" && !first:
+			p.s = ""
+			return lo, hi
+		case c == '+' || c == '-':
+			dir = c
+			p.s = p.s[1:]
+			if c := p.peek(); c == 0 || c == ',' || c == '+' || c == '-' {
+				lo, hi = p.line(dir, lo, hi, 1)
+				first = false
+				continue
+			}
+		}
+		lo, hi = p.term(dir, lo, hi, first)
+		first = false
+	}
+}
+
+// term evaluates a term in the direction dir from the range lo, hi.
+func (p *addrParser) term(dir byte, lo, hi int, absolute bool) (int, int) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.s = p.s[1:]
+		return len(p.src), len(p.src)
+	case c == '#':
+		p.s = p.s[1:]
+		n := p.number()
+		if dir == '-' {
+			n = lo - n
+		} else if !absolute {
+			n += hi
+		}
+		if n < 0 || n > len(p.src) {
+			p.errorf("offset %d out of range", n)
+		}
+		return n, n
+	case '0' <= c && c <= '9':
+		n := p.number()
+		if absolute {
+			return p.line('+', 0, 0, n)
+		}
+		return p.line(dir, lo, hi, n)
+	case c == '/':
+		return p.match(p.regexp(), dir, lo, hi)
+	}
+	p.errorf("unexpected %q", p.s)
+	return 0, 0
+}
+
+// regexp reads a regular expression between slashes, in which \/ is a
+// slash. The closing slash may be left out at the end of the address.
+func (p *addrParser) regexp() *regexp.Regexp {
+	i := 1
+	for i < len(p.s) && p.s[i] != '/' {
+		if p.s[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	expr := p.s[1:min(i, len(p.s))]
+	p.s = p.s[min(i+1, len(p.s)):]
+	re, err := regexp.Compile("(?m:" + expr + ")")
+	if err != nil {
+		p.errorf("%v", err)
+	}
+	return re
+}
+
+func (p *addrParser) number() int {
+	i := 0
+	for i < len(p.s) && '0' <= p.s[i] && p.s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(p.s[:i])
+	if err != nil {
+		p.errorf("bad number %q", p.s[:i])
+	}
+	p.s = p.s[i:]
+	return n
+}
+
+// line returns the range of the line n lines forward or backward of the
+// range lo, hi. Line 0 forward of the start of the file is its start.
+func (p *addrParser) line(dir byte, lo, hi, n int) (int, int) {
+	src := p.src
+	if dir == '-' {
+		start := zhtext.LineStart(src, lo)
+		for ; n > 0; n-- {
+			if start == 0 {
+				p.errorf("line out of range")
+			}
+			start = zhtext.LineStart(src, start-1)
+		}
+		return start, zhtext.LineEnd(src, start)
+	}
+	if n == 0 {
+		return hi, hi
+	}
+	end := hi
+	if end > 0 && src[end-1] != '\n' {
+		end = zhtext.LineEnd(src, end)
+	}
+	start := end
+	for ; n > 0; n-- {
+		if end >= len(src) {
+			p.errorf("line out of range")
+		}
+		start = end
+		end = zhtext.LineEnd(src, end)
+	}
+	return start, end
+}
+
+// match returns the lines of the first match of re after the range lo, hi,
+// or the last one before it, wrapping around the file.
+func (p *addrParser) match(re *regexp.Regexp, dir byte, lo, hi int) (int, int) {
+	var m []int
+	if dir == '-' {
+		all := re.FindAllIndex(p.src[:lo], -1)
+		if len(all) == 0 {
+			all = re.FindAllIndex(p.src, -1)
+		}
+		if len(all) > 0 {
+			m = all[len(all)-1]
+		}
+	} else {
+		if m = re.FindIndex(p.src[hi:]); m != nil {
+			m[0] += hi
+			m[1] += hi
+		} else {
+			m = re.FindIndex(p.src)
+		}
+	}
+	if m == nil {
+		p.errorf("no match for /%s/", strings.TrimSuffix(strings.TrimPrefix(re.String(), "(?m:"), ")"))
+	}
+	end := m[1]
+	if end > m[0] && p.src[end-1] == '\n' {
+		end--
+	}
+	return zhtext.LineStart(p.src, m[0]), zhtext.LineEnd(p.src, end)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,256 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package presentdoc reads the text of the present files of the tour and the
+// talks, the .article and .slide files, for translation.
+//
+// A present file starts with a header, the title and the subtitle, followed
+// by the authors and by sections:
+//
+//	Packages, variables, and functions.
+//	Learn the basic components of any Go program.
+//
+//	The Go Authors
+//	http://golang.org
+//
+//	* Packages
+//
+//	Every Go program is made up of packages.
+//
+//	- a list item
+//
+//	.play basics/packages.go
+//
+// The title, the subtitle, the section headings, the paragraphs and the list
+// items are text, to translate sentence by sentence. The rest is kept as it
+// is: the authors, the directives, which start with a period, the comments,
+// which start with #, such as the #appengine: lines of the tour, and the code
+// blocks, which are indented. A comment in a paragraph splits it in two.
+package presentdoc
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang-china/golangdoc.translations/zhfmt"
+)
+
+// A Kind is the kind of a block.
+type Kind int
+
+const (
+	Other     Kind = iota // lines kept as they are
+	Title                 // the title, first line of the file
+	Subtitle              // the lines of the header that follow the title
+	Heading               // a section heading or slide title, "* Title"
+	Paragraph             // consecutive lines of text
+	Item                  // a list item, "- item"
+)
+
+var kindNames = []string{"other", "title", "subtitle", "heading", "paragraph", "item"}
+
+func (k Kind) String() string { return kindNames[k] }
+
+// A Block is a piece of a present file: text, or lines kept as they are.
+type Block struct {
+	Kind   Kind
+	Line   int    // line of the first line of the block
+	Prefix string // the markup before the text, "* " or "- "
+	Text   string // the text, its lines joined by spaces
+	Src    string // the lines of the block, with their newlines
+}
+
+// IsText reports whether b is text to translate.
+func (b *Block) IsText() bool { return b.Kind != Other }
+
+// Sentences returns the sentences of the text of b.
+func (b *Block) Sentences() []string { return Sentences(b.Text) }
+
+// A Document is a parsed present file.
+type Document struct {
+	Name   string
+	Blocks []*Block
+}
+
+var (
+	headingRx = regexp.MustCompile(`^\*+ `)
+	metaRx    = regexp.MustCompile(`^([A-Z][A-Za-z]*:|[0-9])`) // Tags:, dates
+)
+
+// Parse parses the present file src. The name is only recorded. The blocks
+// of the document hold all of src, in order.
+func Parse(name string, src []byte) *Document {
+	doc := &Document{Name: name}
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var para *Block // paragraph being read
+	add := func(b *Block) {
+		para = nil
+		if n := len(doc.Blocks); b.Kind == Other && n > 0 && doc.Blocks[n-1].Kind == Other {
+			doc.Blocks[n-1].Src += b.Src
+			return
+		}
+		doc.Blocks = append(doc.Blocks, b)
+	}
+	header, body := true, false // in the title and subtitle, after the first heading
+	for i, line := range lines {
+		text := strings.TrimRight(line, " \t\r\n")
+		b := &Block{Line: i + 1, Src: line}
+		switch {
+		case i == 0:
+			b.Kind, b.Text = Title, strings.TrimSpace(text)
+		case header && text != "" && !metaRx.MatchString(text):
+			b.Kind, b.Text = Subtitle, strings.TrimSpace(text)
+		case text == "":
+			header = false
+		case headingRx.MatchString(text):
+			body = true
+			b.Prefix = headingRx.FindString(text)
+			b.Kind, b.Text = Heading, strings.TrimSpace(text[len(b.Prefix):])
+		case !body || header:
+			// Authors and header metadata.
+		case strings.HasPrefix(text, ".") || strings.HasPrefix(text, "#") ||
+			strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " "):
+			// Directives, comments and code.
+		case strings.HasPrefix(text, "- "):
+			b.Kind, b.Prefix, b.Text = Item, "- ", strings.TrimSpace(text[2:])
+		case para != nil:
+			para.Text += " " + strings.TrimSpace(text)
+			para.Src += line
+			continue
+		default:
+			b.Kind, b.Text = Paragraph, strings.TrimSpace(text)
+			add(b)
+			para = b
+			continue
+		}
+		add(b)
+	}
+	return doc
+}
+
+// Translate returns the document with each sentence of its text replaced by
+// its translation, as returned by tr, or left as it is if tr returns "". A
+// block with a translated sentence is written on one line, since the line
+// breaks of a paragraph would put spaces between the Chinese sentences;
+// the other blocks are kept as they are, so that a document without
+// translations is returned unchanged.
+func (d *Document) Translate(tr func(sentence string) string) []byte {
+	var buf bytes.Buffer
+	for _, b := range d.Blocks {
+		if !b.IsText() {
+			buf.WriteString(b.Src)
+			continue
+		}
+		var out []string
+		translated := false
+		for _, s := range b.Sentences() {
+			if t := tr(s); t != "" {
+				s = t
+				translated = true
+			}
+			out = append(out, s)
+		}
+		if !translated {
+			buf.WriteString(b.Src)
+			continue
+		}
+		buf.WriteString(b.Prefix)
+		buf.WriteString(Join(out))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// Join joins sentences: with a space between English sentences, with none
+// next to a Chinese one.
+func Join(sentences []string) string {
+	var buf bytes.Buffer
+	for _, s := range sentences {
+		if buf.Len() > 0 && !zhfmt.Wide(lastRune(buf.String())) && !zhfmt.Wide(firstRune(s)) {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(s)
+	}
+	return buf.String()
+}
+
+// abbreviations end with a period but do not end a sentence.
+var abbreviations = map[string]bool{"e.g.": true, "i.e.": true, "etc.": true, "vs.": true, "cf.": true}
+
+// Sentences splits text into sentences. An English sentence ends with a
+// period, question mark, exclamation mark or colon followed by a space and
+// by a capital letter, a quote, a bracket, a backquote or the markup of bold
+// or italic text; a Chinese one ends with 。？！ or ：. Text in backquotes and
+// in [[links]] is never split.
+func Sentences(text string) []string {
+	var list []string
+	start := 0
+	code, link := false, 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '`':
+			code = !code
+		case code:
+		case strings.HasPrefix(text[i:], "[["):
+			link++
+			size = 2
+		case strings.HasPrefix(text[i:], "]]") && link > 0:
+			link--
+			size = 2
+		case link > 0:
+		case strings.ContainsRune("。？！：", r):
+			end := i + size
+			for end < len(text) && text[end] == ' ' {
+				end++
+			}
+			if end < len(text) {
+				list = append(list, strings.TrimSpace(text[start:end]))
+				start = end
+			}
+		case strings.ContainsRune(".?!:", r) && endsSentence(text, start, i+size):
+			list = append(list, strings.TrimSpace(text[start:i+size]))
+			start = i + size
+		}
+		i += size
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		list = append(list, s)
+	}
+	return list
+}
+
+// endsSentence reports whether the punctuation ending at text[end] ends the
+// sentence that starts at text[start].
+func endsSentence(text string, start, end int) bool {
+	if end >= len(text) || text[end] != ' ' {
+		return false
+	}
+	word := text[start:end]
+	if i := strings.LastIndexByte(word, ' '); i >= 0 {
+		word = word[i+1:]
+	}
+	if abbreviations[strings.ToLower(word)] {
+		return false
+	}
+	next := strings.TrimLeft(text[end:], " ")
+	r := firstRune(next)
+	return unicode.IsUpper(r) || strings.ContainsRune("\"'([`*_", r) || unicode.Is(unicode.Han, r)
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
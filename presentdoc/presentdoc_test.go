@@ -0,0 +1,177 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package presentdoc
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testArticle = `Packages, variables, and functions.
+Learn the basic components of any Go program.
+
+The Go Authors
+http://golang.org
+
+* Packages
+
+Every Go program is made up of packages. Programs start
+running in package ` + "`main`" + `, e.g. this one.
+
+#appengine: *Note:* the environment is
+#appengine: deterministic.
+
+.play basics/packages.go
+
+- one item
+- [[http://golang.org/][Go. Really.]] Yes.
+
+	import "fmt"
+`
+
+func TestParse(t *testing.T) {
+	doc := Parse("basics.article", []byte(testArticle))
+	type block struct {
+		kind Kind
+		line int
+		text string
+	}
+	var got []block
+	for _, b := range doc.Blocks {
+		if b.IsText() {
+			got = append(got, block{b.Kind, b.Line, b.Text})
+		}
+	}
+	want := []block{
+		{Title, 1, "Packages, variables, and functions."},
+		{Subtitle, 2, "Learn the basic components of any Go program."},
+		{Heading, 7, "Packages"},
+		{Paragraph, 9, "Every Go program is made up of packages. Programs start running in package `main`, e.g. this one."},
+		{Item, 17, "one item"},
+		{Item, 18, "[[http://golang.org/][Go. Really.]] Yes."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if s := string(doc.Translate(func(string) string { return "" })); s != testArticle {
+		t.Errorf("Translate without translations changed the document:\n%s", s)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want []string
+	}{
+		{"One. Two? Three! `a. B` four.", []string{"One.", "Two?", "Three!", "`a. B` four."}},
+		{"Use e.g. This, i.e. That. Like: Here.", []string{"Use e.g. This, i.e. That.", "Like:", "Here."}},
+		{"See [[x][a. B]]. Done.", []string{"See [[x][a. B]].", "Done."}},
+		{"in the range 1. 2 is not.", []string{"in the range 1. 2 is not."}},
+		{"第一句。第二句！", []string{"第一句。", "第二句！"}},
+	} {
+		if got := Sentences(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Sentences(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	doc := Parse("basics.article", []byte(testArticle))
+	tr := map[string]string{
+		"Packages": "包",
+		"Every Go program is made up of packages.": "每个 Go 程序都是由包构成的。",
+		"one item": "一项",
+	}
+	got := string(doc.Translate(func(s string) string { return tr[s] }))
+	want := `Packages, variables, and functions.
+Learn the basic components of any Go program.
+
+The Go Authors
+http://golang.org
+
+* 包
+
+每个 Go 程序都是由包构成的。Programs start running in package ` + "`main`" + `, e.g. this one.
+
+#appengine: *Note:* the environment is
+#appengine: deterministic.
+
+.play basics/packages.go
+
+- 一项
+- [[http://golang.org/][Go. Really.]] Yes.
+
+	import "fmt"
+`
+	if got != want {
+		t.Errorf("Translate:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+const testCode = `package main
+
+// START OMIT
+import "fmt"
+
+// STOP OMIT
+
+func main() {
+	fmt.Println("hi") // HLprint
+}
+`
+
+func TestAddress(t *testing.T) {
+	for _, tt := range []struct {
+		addr, want string
+	}{
+		{"/START/,/STOP/", "// START OMIT\nimport \"fmt\"\n\n// STOP OMIT\n"},
+		{"/func main/,/^}/", "func main() {\n\tfmt.Println(\"hi\") // HLprint\n}\n"},
+		{"/func main/,", "func main() {\n\tfmt.Println(\"hi\") // HLprint\n}\n"},
+		{"/func main/+1", "\tfmt.Println(\"hi\") // HLprint\n"},
+		{"/^}/-1", "\tfmt.Println(\"hi\") // HLprint\n"},
+		{"/START/+1,/STOP/-2/", "import \"fmt\"\n"},
+		{`/\/\/.HL/`, "\tfmt.Println(\"hi\") // HLprint\n"},
+		{"3,4", "// START OMIT\nimport \"fmt\"\n"},
+		{"#0,$", testCode},
+		{"/END/", "error: address /END/: no match for /END/"},
+		{"/main(/", "error"},
+		{"9,2", "error: address 9,2: end before start"},
+	} {
+		lo, hi, err := Address([]byte(testCode), tt.addr)
+		got := ""
+		if err != nil {
+			got = "error: " + err.Error()
+			if tt.want == "error" {
+				continue
+			}
+		} else {
+			got = testCode[lo:hi]
+		}
+		if got != tt.want {
+			t.Errorf("Address(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	doc := Parse("x.slide", []byte("Title\n\n* Slide\n\n.code -edit x.go /START/,/STOP/ HLprint\n.play x.go\n.image x.png\n"))
+	code := doc.Code()
+	if len(code) != 2 {
+		t.Fatalf("got %d directives, want 2", len(code))
+	}
+	c := code[0]
+	if c.Line != 5 || c.Cmd != "code" || len(c.Flags) != 1 || c.File != "x.go" || c.Addr != "/START/,/STOP/" || c.HL != "HLprint" {
+		t.Errorf("code = %+v", c)
+	}
+	if _, err := c.Select([]byte(testCode)); err == nil {
+		t.Errorf("Select found the HLprint marker outside of its address")
+	}
+	if c := code[1]; c.Cmd != "play" || c.Addr != "" {
+		t.Errorf("play = %+v", c)
+	}
+	if _, err := code[1].Select([]byte(testCode)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+// AddStubs adds the documented units of the stub tree below root, each
+// under its identifier, such as strings.Index or strings.Reader.Len. The
+// Chinese text of untranslated units is empty.
+func (x *Index) AddStubs(root string) error {
+	fset := token.NewFileSet()
+	return docstub.Walk(fset, root, func(pkg *docstub.Package, err error) error {
+		if err != nil {
+			return err
+		}
+		ids := pkg.IDs()
+		qual := path.Base(pkg.ImportPath)
+		for i, u := range pkg.Units() {
+			if !u.Documented() {
+				continue
+			}
+			d := &Doc{
+				ID:      ids[i],
+				English: u.English,
+				Chinese: u.Chinese,
+				Source:  filepath.ToSlash(u.Pos.Filename) + ":" + strconv.Itoa(u.Pos.Line),
+			}
+			if u.Kind == docstub.PackageClause {
+				d.Names = []string{pkg.ImportPath}
+				if qual != pkg.ImportPath {
+					d.Names = append(d.Names, qual)
+				}
+			} else {
+				d.Names = append(d.Names, qual+"."+u.Name)
+				for _, name := range u.Names[1:] {
+					d.Names = append(d.Names, qual+"."+name)
+				}
+			}
+			x.Add(d)
+		}
+		return nil
+	})
+}
+
+// AddHTML adds the pairs of the bilingual HTML documents below dir. A pair
+// is found under the name of its document and the id of the heading it
+// follows, such as doc/zh_CN/go_faq.html#goroutines.
+func (x *Index) AddHTML(dir string) error {
+	return filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(name, ".html") {
+			return err
+		}
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		doc := htmldoc.Parse(name, src)
+		file := filepath.ToSlash(name)
+		id, title := file, ""
+		for _, p := range doc.Pairs {
+			for _, e := range p.English {
+				if isHeading(e.Tag) {
+					title = e.Text()
+					if e.ID != "" {
+						id = file + "#" + e.ID
+					}
+				}
+			}
+			for _, e := range p.Chinese {
+				if isHeading(e.Tag) && e.ID != "" {
+					id = file + "#" + e.ID
+				}
+			}
+			en, zh := p.EnglishText(), p.ChineseText()
+			if en == "" && zh == "" {
+				continue
+			}
+			x.Add(&Doc{
+				ID:      id,
+				Title:   title,
+				English: en,
+				Chinese: zh,
+				Source:  file + ":" + strconv.Itoa(p.Line()),
+			})
+		}
+		return nil
+	})
+}
+
+func isHeading(tag string) bool {
+	return len(tag) == 2 && tag[0] == 'h' && '1' <= tag[1] && tag[1] <= '6'
+}
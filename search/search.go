@@ -0,0 +1,302 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package search is a full-text index of the translated documentation: the
+// declarations of the doc stubs and the paragraphs of the bilingual HTML
+// documents, searched in English and in Chinese.
+//
+// English text is split into words, and Chinese text, which has no spaces
+// between words, into bigrams: 切片容量 is indexed as 切片, 片容 and 容量, so
+// that the query 切片 容量 finds it without a dictionary. The hits that
+// declare an identifier of the query, such as Index for the query
+// strings.Index, come first; the others are ranked by BM25 relevance.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Doc is an indexed document: a declaration of a stub, or a paragraph of
+// an HTML document.
+type Doc struct {
+	ID      string   `json:"id"`              // "strings.Index", or "doc/zh_CN/go_faq.html#anchor"
+	Names   []string `json:"names,omitempty"` // identifiers declared, qualified by the package name
+	Title   string   `json:"title,omitempty"` // heading of an HTML paragraph
+	English string   `json:"english"`
+	Chinese string   `json:"chinese"`
+	Source  string   `json:"source"` // file:line
+
+	length int // number of tokens
+}
+
+// A Hit is a document found for a query.
+type Hit struct {
+	*Doc
+	Ident          int     `json:"ident"` // 2 for a qualified identifier match, 1 for an unqualified one, else 0
+	Score          float64 `json:"score"` // text relevance
+	EnglishSnippet string  `json:"english_snippet"`
+	ChineseSnippet string  `json:"chinese_snippet"`
+	Matches        int     `json:"matches"` // number of distinct query tokens found
+}
+
+// A posting records the occurrences of a token in a document.
+type posting struct {
+	doc, count int
+}
+
+// An Index indexes documents by their tokens and identifiers.
+type Index struct {
+	Docs []*Doc
+
+	postings map[string][]posting
+	idents   map[string][]int // documents by lower case identifier
+	tokens   int              // total number of tokens, for the average length
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{
+		postings: make(map[string][]posting),
+		idents:   make(map[string][]int),
+	}
+}
+
+// Add adds d to the index.
+func (x *Index) Add(d *Doc) {
+	n := len(x.Docs)
+	x.Docs = append(x.Docs, d)
+	counts := make(map[string]int)
+	for _, t := range Tokens(d.English + "\n" + d.Chinese) {
+		counts[t]++
+		d.length++
+	}
+	x.tokens += d.length
+	toks := make([]string, 0, len(counts))
+	for t := range counts {
+		toks = append(toks, t)
+	}
+	sort.Strings(toks)
+	for _, t := range toks {
+		x.postings[t] = append(x.postings[t], posting{n, counts[t]})
+	}
+	for _, name := range d.Names {
+		for _, key := range identKeys(name) {
+			if list := x.idents[key]; len(list) == 0 || list[len(list)-1] != n {
+				x.idents[key] = append(list, n)
+			}
+		}
+	}
+}
+
+// identKeys returns the lower case keys of a qualified identifier such as
+// strings.Reader.Len: the identifier itself and its unqualified forms
+// Reader.Len and Len.
+func identKeys(name string) []string {
+	name = strings.ToLower(name)
+	keys := []string{name}
+	for i := strings.Index(name, "."); i >= 0; i = strings.Index(name, ".") {
+		name = name[i+1:]
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Search returns the n best hits for query: first the documents that
+// declare one of its words as an identifier, qualified matches first, then
+// the documents that hold its tokens, by relevance.
+func (x *Index) Search(query string, n int) []*Hit {
+	if n <= 0 || len(x.Docs) == 0 {
+		return nil
+	}
+	hits := make(map[int]*Hit)
+	hit := func(i int) *Hit {
+		h := hits[i]
+		if h == nil {
+			h = &Hit{Doc: x.Docs[i]}
+			hits[i] = h
+		}
+		return h
+	}
+
+	for _, f := range strings.Fields(query) {
+		f = strings.ToLower(strings.Trim(f, ".,;:()"))
+		for _, i := range x.idents[f] {
+			ident := 1
+			if strings.Contains(f, ".") || len(x.Docs[i].Names) > 0 && strings.ToLower(x.Docs[i].Names[0]) == f {
+				ident = 2
+			}
+			if h := hit(i); ident > h.Ident {
+				h.Ident = ident
+			}
+		}
+	}
+
+	terms := distinct(Tokens(query))
+	avg := float64(x.tokens) / float64(len(x.Docs))
+	for _, t := range terms {
+		list := x.postings[t]
+		if len(list) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(len(x.Docs))-float64(len(list))+0.5)/(float64(len(list))+0.5))
+		for _, p := range list {
+			tf := float64(p.count)
+			norm := k1 * (1 - b + b*float64(x.Docs[p.doc].length)/avg)
+			h := hit(p.doc)
+			h.Score += idf * tf * (k1 + 1) / (tf + norm)
+			h.Matches++
+		}
+	}
+
+	list := make([]*Hit, 0, len(hits))
+	for _, h := range hits {
+		list = append(list, h)
+	}
+	sort.Sort(byRank(list))
+	if len(list) > n {
+		list = list[:n]
+	}
+	for _, h := range list {
+		h.EnglishSnippet = Snippet(h.English, terms)
+		h.ChineseSnippet = Snippet(h.Chinese, terms)
+	}
+	return list
+}
+
+// byRank sorts hits by identifier match, then by the number of query tokens
+// found, then by relevance.
+type byRank []*Hit
+
+func (s byRank) Len() int      { return len(s) }
+func (s byRank) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byRank) Less(i, j int) bool {
+	x, y := s[i], s[j]
+	switch {
+	case x.Ident != y.Ident:
+		return x.Ident > y.Ident
+	case x.Matches != y.Matches:
+		return x.Matches > y.Matches
+	case x.Score != y.Score:
+		return x.Score > y.Score
+	}
+	return x.ID < y.ID
+}
+
+func distinct(list []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Tokens returns the tokens of s: the lower case English words, with a
+// plural s removed, and the bigrams of each run of Chinese characters; a
+// Chinese character alone between other text is a token of its own.
+func Tokens(s string) []string {
+	var toks []string
+	var word, han []rune
+	flush := func() {
+		if len(word) > 0 {
+			toks = append(toks, stem(string(word)))
+			word = word[:0]
+		}
+		switch len(han) {
+		case 0:
+		case 1:
+			toks = append(toks, string(han))
+		default:
+			for i := 0; i+1 < len(han); i++ {
+				toks = append(toks, string(han[i:i+2]))
+			}
+		}
+		han = han[:0]
+	}
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if len(word) > 0 {
+				flush()
+			}
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if len(han) > 0 {
+				flush()
+			}
+			word = append(word, unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return toks
+}
+
+// stem removes the plural s of an English word: goroutines, leaks.
+func stem(w string) string {
+	if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+// snippetLen is the length of a snippet, in runes.
+const snippetLen = 80
+
+// Snippet returns about snippetLen runes of text around the first
+// occurrence of one of the tokens, with white space collapsed. Elided text
+// is marked with an ellipsis.
+func Snippet(text string, tokens []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(text)
+	at := -1
+	for _, t := range tokens {
+		if i := strings.Index(lower, t); i >= 0 && (at < 0 || i < at) {
+			at = i
+		}
+	}
+	if at < 0 || at > len(text) {
+		at = 0
+	}
+	// Start at the beginning of the text if the match is near it, or
+	// else a little before the match, at a word boundary.
+	start := 0
+	if utf8.RuneCountInString(text[:at]) > snippetLen/2 {
+		start = at
+		for n := 0; n < snippetLen/4; n++ {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+		if i := strings.IndexByte(text[start:at], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+	end := start
+	for n := 0; end < len(text) && n < snippetLen; n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	s := text[start:end]
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(text) {
+		s += "…"
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	got := Tokens("Goroutines leak: 切片的容量 cap(s) 是x个")
+	want := []string{"goroutine", "leak", "切片", "片的", "的容", "容量", "cap", "s", "是", "x", "个"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens = %q, want %q", got, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("word ", 30) + "match here " + strings.Repeat("tail ", 30)
+	got := Snippet(text, []string{"match"})
+	if !strings.HasPrefix(got, "…word word") || !strings.Contains(got, "match here") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Snippet = %q", got)
+	}
+	if got, want := Snippet("Short  text\nhere.", []string{"none"}), "Short text here."; got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	x := New()
+	for _, d := range []*Doc{
+		{ID: "strings.Index", Names: []string{"strings.Index"}, English: "Index returns the index of the first instance of sep in s.", Chinese: "Index 返回 sep 在 s 中第一次出现的位置。"},
+		{ID: "bytes.Index", Names: []string{"bytes.Index"}, English: "Index returns the index of the first instance of sep in s.", Chinese: "Index 返回 sep 在 s 中第一次出现的位置。"},
+		{ID: "builtin.cap", Names: []string{"builtin.cap"}, English: "The cap built-in function returns the capacity of v.", Chinese: "内建函数 cap 返回 v 的容量。切片的容量是其底层数组的长度。"},
+		{ID: "builtin.append", Names: []string{"builtin.append"}, English: "The append built-in function appends elements to the end of a slice.", Chinese: "内建函数 append 将元素追加到切片的末尾。"},
+		{ID: "doc/faq.html#leaks", English: "A goroutine blocked forever on a channel leaks.", Chinese: "永远阻塞在信道上的 goroutine 会泄漏。"},
+		{ID: "doc/faq.html#goroutines", English: "Goroutines are cheap.", Chinese: "goroutine 的开销很小。"},
+	} {
+		x.Add(d)
+	}
+	for _, tt := range []struct {
+		query string
+		want  []string
+	}{
+		{"strings.Index", []string{"strings.Index", "bytes.Index"}},
+		{"Index", []string{"bytes.Index", "strings.Index"}},
+		{"切片 容量", []string{"builtin.cap", "builtin.append"}},
+		{"goroutine leak", []string{"doc/faq.html#leaks", "doc/faq.html#goroutines"}},
+		{"泄漏", []string{"doc/faq.html#leaks"}},
+		{"nothing", nil},
+	} {
+		var got []string
+		for _, h := range x.Search(tt.query, 10) {
+			got = append(got, h.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+	hits := x.Search("容量", 1)
+	if len(hits) != 1 || hits[0].ChineseSnippet != hits[0].Chinese || hits[0].EnglishSnippet != hits[0].English {
+		t.Errorf("Search(容量) = %+v", hits)
+	}
+}
+
+const testHTML = `<h2 id="slices">Slices</h2>
+<div class="english">
+<h2 id="Slices">Slices</h2>
+<p>
+Slices wrap arrays.
+</p>
+</div>
+<h2 id="切片">切片</h2>
+<p>
+切片封装了数组。
+</p>
+`
+
+func TestAddHTML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "search")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "doc.html")
+	if err := ioutil.WriteFile(name, []byte(testHTML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	x := New()
+	if err := x.AddHTML(dir); err != nil {
+		t.Fatal(err)
+	}
+	hits := x.Search("封装", 10)
+	if len(hits) != 1 {
+		t.Fatalf("Search(封装) = %d hits, want 1", len(hits))
+	}
+	h := hits[0]
+	if want := filepath.ToSlash(name) + "#切片"; h.ID != want || h.Title != "Slices" || h.English != "Slices\nSlices wrap arrays." {
+		t.Errorf("hit = %s %q %q, want %s", h.ID, h.Title, h.English, want)
+	}
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package builtin provides documentation for Go's predeclared identifiers. The
 // items documented here are not actually in package builtin but their descriptions
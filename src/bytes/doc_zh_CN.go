@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package bytes implements functions for the manipulation of byte slices. It is
 // analogous to the facilities of the strings package.
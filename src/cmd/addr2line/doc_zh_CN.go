@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Addr2line is a minimal simulation of the GNU addr2line tool, just enough to
 // support pprof.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Cover is a program for analyzing the coverage profiles generated by 'go test
 // -coverprofile=cover.out'.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Fix finds Go programs that use old APIs and rewrites them to use newer ones.
 // After you update to a new Go release, fix helps make the necessary changes to
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Gofmt formats Go programs. It uses tabs (width = 8) for indentation and blanks
 // for alignment.
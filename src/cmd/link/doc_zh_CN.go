@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // TODO(rsc): Handle go.typelink, go.track symbols. TODO(rsc): Do not handle $f64.
 // and $f32. symbols. Instead, generate those from the compiler and assemblers as
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Pack is a simple version of the traditional Unix ar tool. It implements only the
 // operations needed by Go.
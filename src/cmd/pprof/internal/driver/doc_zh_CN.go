@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package driver implements the core pprof functionality. It can be parameterized
 // with a flag implementation, fetch and symbolize mechanisms.
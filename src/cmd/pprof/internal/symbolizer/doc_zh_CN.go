@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package symbolizer provides a routine to populate a profile with symbol, file
 // and line number information. It relies on the addr2liner and demangler packages
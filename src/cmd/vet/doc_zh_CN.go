@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Vet examines Go source code and reports suspicious constructs, such as Printf
 // calls whose arguments do not align with the format string. Vet uses heuristics
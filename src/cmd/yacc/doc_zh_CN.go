@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Yacc is a version of yacc for Go. It is written in Go and generates parsers
 // written in Go.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package gzip implements reading and writing of gzip format compressed files, as
 // specified in RFC 1952.
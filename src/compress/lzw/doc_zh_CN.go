@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package lzw implements the Lempel-Ziv-Welch compressed data format, described in
 // T. A. Welch, ``A Technique for High-Performance Data Compression'', Computer,
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package cipher implements standard block cipher modes that can be wrapped around
 // low-level block cipher implementations. See
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package des implements the Data Encryption Standard (DES) and the Triple Data
 // Encryption Algorithm (TDEA) as defined in U.S. Federal Information Processing
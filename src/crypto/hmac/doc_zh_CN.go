@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package hmac implements the Keyed-Hash Message Authentication Code (HMAC) as
 // defined in U.S. Federal Information Processing Standards Publication 198. An
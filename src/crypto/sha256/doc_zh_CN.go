@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package sha256 implements the SHA224 and SHA256 hash algorithms as defined in
 // FIPS 180-4.
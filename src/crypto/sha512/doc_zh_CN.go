@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package sha512 implements the SHA384 and SHA512 hash algorithms as defined in
 // FIPS 180-2.
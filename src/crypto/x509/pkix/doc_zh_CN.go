@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package pkix contains shared, low level structures used for ASN.1 parsing and
 // serialization of X.509 certificates, CRL and OCSP.
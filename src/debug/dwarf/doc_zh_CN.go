@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package dwarf provides access to DWARF debugging information loaded from
 // executable files, as defined in the DWARF 2.0 Standard at
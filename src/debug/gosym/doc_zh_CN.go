@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package gosym implements access to the Go symbol and line number tables embedded
 // in Go binaries generated by the gc compilers.
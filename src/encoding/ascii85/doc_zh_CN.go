@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package ascii85 implements the ascii85 data encoding as used in the btoa tool
 // and Adobe's PostScript and PDF document formats.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package asn1 implements parsing of DER-encoded ASN.1 data structures, as defined
 // in ITU-T Rec X.690.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package binary implements simple translation between numbers and byte sequences
 // and encoding and decoding of varints.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package encoding defines interfaces shared by other packages that convert data
 // to and from byte-level and textual representations. Packages that check for
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package gob manages streams of gobs - binary values exchanged between an Encoder
 // (transmitter) and a Decoder (receiver). A typical use is transporting arguments
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package json implements encoding and decoding of JSON objects as defined in RFC
 // 4627. The mapping between JSON objects and Go values is described in the
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package expvar provides a standardized interface to public variables, such as
 // operation counters in servers. It exposes these variables via HTTP at
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package fmt implements formatted I/O with functions analogous to C's printf and
 // scanf. The format 'verbs' are derived from C's but are simpler.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package parser implements a parser for Go source files. Input may be provided in
 // a variety of forms (see the various Parse* functions); the output is an abstract
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package scanner implements a scanner for Go source text. It takes a []byte as
 // source which can then be tokenized through repeated calls to the Scan method.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package riff implements the Resource Interchange File Format, used by media
 // formats such as AVI, WAVE and WEBP.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package nycbcra provides non-alpha-premultiplied Y'CbCr-with-alpha image and
 // color types.
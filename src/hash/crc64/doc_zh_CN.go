@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package crc64 implements the 64-bit cyclic redundancy check, or CRC-64,
 // checksum. See http://en.wikipedia.org/wiki/Cyclic_redundancy_check for
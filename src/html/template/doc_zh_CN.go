@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package template (html/template) implements data-driven templates for generating
 // HTML output safe against code injection. It provides the same interface as
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package io provides basic interfaces to I/O primitives. Its primary job is to
 // wrap existing implementations of such primitives, such as those in package os,
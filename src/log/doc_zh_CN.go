@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package log implements a simple logging package. It defines a type, Logger, with
 // methods for formatting output. It also has a predefined 'standard' Logger
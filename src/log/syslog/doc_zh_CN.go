@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package syslog provides a simple interface to the system log service. It can
 // send messages to the syslog daemon using UNIX domain sockets, UDP or TCP.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package net provides a portable interface for network I/O, including TCP/IP,
 // UDP, domain name resolution, and Unix domain sockets.
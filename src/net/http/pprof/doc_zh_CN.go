@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package pprof serves via its HTTP server runtime profiling data in the format
 // expected by the pprof visualization tool. For more information about pprof, see
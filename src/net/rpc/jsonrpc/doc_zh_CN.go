@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package jsonrpc implements a JSON-RPC ClientCodec and ServerCodec for the rpc
 // package.
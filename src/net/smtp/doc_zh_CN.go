@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package smtp implements the Simple Mail Transfer Protocol as defined in RFC
 // 5321. It also implements the following extensions:
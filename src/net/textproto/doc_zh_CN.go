@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package textproto implements generic support for text-based request/response
 // protocols in the style of HTTP, NNTP, and SMTP.
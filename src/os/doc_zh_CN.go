@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package os provides a platform-independent interface to operating system
 // functionality. The design is Unix-like, although the error handling is Go-like;
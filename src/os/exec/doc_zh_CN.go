@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package exec runs external commands. It wraps os.StartProcess to make it easier
 // to remap stdin and stdout, connect I/O with pipes, and do other adjustments.
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package reflect implements run-time reflection, allowing a program to manipulate
 // objects with arbitrary types. The typical use is to take a value with static
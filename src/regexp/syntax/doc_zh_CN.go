@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package syntax parses regular expressions into parse trees and compiles parse
 // trees into programs. Most clients of regular expressions will use the facilities
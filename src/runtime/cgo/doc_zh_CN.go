@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package cgo contains runtime support for code generated by the cgo tool. See the
 // documentation for the cgo command for details on using cgo.
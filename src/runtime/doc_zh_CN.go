@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package runtime contains operations that interact with Go's runtime system, such
 // as functions to control goroutines. It also includes the low-level type
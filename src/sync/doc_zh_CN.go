@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package sync provides basic synchronization primitives such as mutual exclusion
 // locks. Other than the Once and WaitGroup types, most are intended for use by
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package syscall contains an interface to the low-level operating system
 // primitives. The details vary depending on the underlying system, and by default,
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package testing provides support for automated testing of Go packages. It is
 // intended to be used in concert with the ``go test'' command, which automates
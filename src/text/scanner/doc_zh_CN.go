@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package scanner provides a scanner and tokenizer for UTF-8-encoded text. It
 // takes an io.Reader providing the source, which then can be tokenized through
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build ingore
+// +build ignore
 
 // Package parse builds parse trees for templates as defined by text/template and
 // html/template. Clients should use those packages to construct templates rather
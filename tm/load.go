@@ -0,0 +1,120 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tm
+
+import (
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+// AddStubs adds the translated units of the stub tree below root. Fuzzy
+// translations are left out. See AddSegments for how comments are split.
+func (m *Memory) AddStubs(root string) error {
+	fset := token.NewFileSet()
+	return docstub.Walk(fset, root, func(pkg *docstub.Package, err error) error {
+		if err != nil {
+			return err
+		}
+		ids := pkg.IDs()
+		for i, u := range pkg.Units() {
+			if u.English == "" || !u.Translated() || u.Fuzzy() {
+				continue
+			}
+			source := filepath.ToSlash(u.Pos.Filename) + ":" + strconv.Itoa(u.Pos.Line)
+			m.AddSegments(u.English, u.Chinese, source, ids[i])
+		}
+		return nil
+	})
+}
+
+// AddHTML adds the pairs of the bilingual HTML documents in dir.
+func (m *Memory) AddHTML(dir string) error {
+	names, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		doc := htmldoc.Parse(name, src)
+		for _, p := range doc.Pairs {
+			source := filepath.ToSlash(name) + ":" + strconv.Itoa(p.Line())
+			m.AddSegments(p.EnglishText(), p.ChineseText(), source, "")
+		}
+	}
+	return nil
+}
+
+// AddSegments adds a translation and its parts. If the English and the
+// Chinese text have the same number of paragraphs, each pair of paragraphs is
+// added too, and likewise each pair of sentences of paragraphs with the same
+// number of sentences, so that a paragraph or sentence repeated in other
+// comments is found on its own.
+func (m *Memory) AddSegments(english, chinese, source, id string) {
+	m.Add(english, chinese, source, id)
+	en, zh := Paragraphs(english), Paragraphs(chinese)
+	if len(en) != len(zh) {
+		return
+	}
+	for i := range en {
+		if len(en) > 1 {
+			m.Add(en[i], zh[i], source, id)
+		}
+		ens, zhs := Sentences(en[i]), Sentences(zh[i])
+		if len(ens) > 1 && len(ens) == len(zhs) {
+			for j := range ens {
+				m.Add(ens[j], zhs[j], source, id)
+			}
+		}
+	}
+}
+
+// Paragraphs splits doc comment text at its blank lines.
+func Paragraphs(text string) []string {
+	var paras []string
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paras = append(paras, p)
+		}
+	}
+	return paras
+}
+
+// Sentences splits English or Chinese text into sentences. An English
+// sentence ends in a period, question or exclamation mark followed by a space
+// and an upper case letter; a Chinese one ends in a full width mark.
+func Sentences(text string) []string {
+	var list []string
+	rs := []rune(text)
+	start := 0
+	for i, r := range rs {
+		end := false
+		switch r {
+		case '。', '？', '！':
+			end = true
+		case '.', '?', '!':
+			end = i+2 < len(rs) && unicode.IsSpace(rs[i+1]) && unicode.IsUpper(rs[i+2])
+		}
+		if end {
+			if s := strings.TrimSpace(string(rs[start : i+1])); s != "" {
+				list = append(list, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(string(rs[start:])); s != "" {
+		list = append(list, s)
+	}
+	return list
+}
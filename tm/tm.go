@@ -0,0 +1,231 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tm is a translation memory: an index of the English and Chinese
+// pairs already in the repository, queried for the translations of similar
+// English text.
+//
+// Similarity is the word level edit distance of the two English texts,
+// normalized to a score between 0 (nothing in common) and 1 (same words).
+// Case, punctuation and white space are ignored.
+package tm
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// An Entry is an English text with its translation.
+type Entry struct {
+	English string   `json:"english"`
+	Chinese string   `json:"chinese"`
+	Sources []string `json:"sources"` // file:line of each occurrence
+	ID      string   `json:"id,omitempty"`
+
+	words []string
+}
+
+// A Match is an entry found for a query.
+type Match struct {
+	*Entry
+	Score float64 `json:"score"`
+}
+
+// A Memory indexes entries by the words of their English text.
+type Memory struct {
+	Entries []*Entry
+
+	pairs    map[[2]string]*Entry // entries by English and Chinese text
+	postings map[string][]int     // indexes of the entries holding each word
+}
+
+// New returns an empty memory.
+func New() *Memory {
+	return &Memory{
+		pairs:    make(map[[2]string]*Entry),
+		postings: make(map[string][]int),
+	}
+}
+
+// Add adds a translation found at source to m. Adding the same translation
+// again only records the source.
+func (m *Memory) Add(english, chinese, source, id string) {
+	english, chinese = strings.TrimSpace(english), strings.TrimSpace(chinese)
+	if english == "" || chinese == "" {
+		return
+	}
+	key := [2]string{english, chinese}
+	if e := m.pairs[key]; e != nil {
+		e.Sources = append(e.Sources, source)
+		return
+	}
+	e := &Entry{English: english, Chinese: chinese, Sources: []string{source}, ID: id, words: Words(english)}
+	if len(e.words) == 0 {
+		return
+	}
+	m.pairs[key] = e
+	n := len(m.Entries)
+	m.Entries = append(m.Entries, e)
+	for _, w := range distinct(e.words) {
+		m.postings[w] = append(m.postings[w], n)
+	}
+}
+
+// maxCandidates is the number of entries scored for a query, picked by the
+// number of words they share with it.
+const maxCandidates = 500
+
+// Lookup returns the entries whose English text scores at least min against
+// english, best first, at most n of them.
+func (m *Memory) Lookup(english string, n int, min float64) []*Match {
+	words := Words(english)
+	if len(words) == 0 || n <= 0 {
+		return nil
+	}
+
+	// Count the words each entry shares with the query. Words found in
+	// a good part of the memory say little and are skipped, unless the
+	// query has nothing else.
+	shared := make(map[int]int)
+	common := len(m.Entries)/8 + 1
+	qwords := distinct(words)
+	for pass := 0; pass < 2 && len(shared) == 0; pass++ {
+		for _, w := range qwords {
+			if p := m.postings[w]; pass == 1 || len(p) <= common {
+				for _, i := range p {
+					shared[i]++
+				}
+			}
+		}
+	}
+	cands := make([]int, 0, len(shared))
+	for i := range shared {
+		cands = append(cands, i)
+	}
+	sort.Sort(byShared{cands, shared})
+	if len(cands) > maxCandidates {
+		cands = cands[:maxCandidates]
+	}
+
+	var matches []*Match
+	for _, i := range cands {
+		e := m.Entries[i]
+		if bound(len(words), len(e.words)) < min {
+			continue
+		}
+		if s := Score(words, e.words); s >= min {
+			matches = append(matches, &Match{e, s})
+		}
+	}
+	sort.Stable(byScore(matches))
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// byShared sorts entry indexes by the number of words shared with a query.
+type byShared struct {
+	list   []int
+	shared map[int]int
+}
+
+func (s byShared) Len() int      { return len(s.list) }
+func (s byShared) Swap(i, j int) { s.list[i], s.list[j] = s.list[j], s.list[i] }
+func (s byShared) Less(i, j int) bool {
+	x, y := s.list[i], s.list[j]
+	if s.shared[x] != s.shared[y] {
+		return s.shared[x] > s.shared[y]
+	}
+	return x < y
+}
+
+// byScore sorts matches best first; of equal scores, the translation used
+// most often comes first.
+type byScore []*Match
+
+func (s byScore) Len() int      { return len(s) }
+func (s byScore) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byScore) Less(i, j int) bool {
+	if s[i].Score != s[j].Score {
+		return s[i].Score > s[j].Score
+	}
+	return len(s[i].Sources) > len(s[j].Sources)
+}
+
+// Words returns the lower case words of s, the tokens the scores are
+// computed on. Identifiers such as io.Reader are kept as one word.
+func Words(s string) []string {
+	f := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+	})
+	words := f[:0]
+	for _, w := range f {
+		if w = strings.Trim(w, "."); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Score returns the similarity of two word lists: 1 minus their edit
+// distance divided by the length of the longer one.
+func Score(a, b []string) float64 {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(distance(a, b))/float64(n)
+}
+
+// bound is the best score two lists of la and lb words can reach.
+func bound(la, lb int) float64 {
+	if la > lb {
+		la, lb = lb, la
+	}
+	return float64(la) / float64(lb)
+}
+
+// distance returns the Levenshtein distance of a and b, counted in words.
+func distance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			d := prev[j-1]
+			if a[i-1] != b[j-1] {
+				d++
+			}
+			if x := prev[j] + 1; x < d {
+				d = x
+			}
+			if x := cur[j-1] + 1; x < d {
+				d = x
+			}
+			cur[j] = d
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func distinct(words []string) []string {
+	seen := make(map[string]bool)
+	var list []string
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			list = append(list, w)
+		}
+	}
+	return list
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	got := Words("Read reads up to len(p) bytes; see io.Reader.")
+	want := []string{"read", "reads", "up", "to", "len", "p", "bytes", "see", "io.reader"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
+
+func TestScore(t *testing.T) {
+	for _, tt := range []struct {
+		a, b  string
+		score float64
+	}{
+		{"a b c d", "a b c d", 1},
+		{"a b c d", "a x c d", 0.75},
+		{"a b c d", "a b c", 0.75},
+		{"a b", "c d", 0},
+		{"", "", 1},
+	} {
+		if got := Score(Words(tt.a), Words(tt.b)); got != tt.score {
+			t.Errorf("Score(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.score)
+		}
+	}
+}
+
+func TestSentences(t *testing.T) {
+	got := Sentences("It reads p. See io.Reader for details.\nOr not?")
+	want := []string{"It reads p.", "See io.Reader for details.", "Or not?"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("English: got %q, want %q", got, want)
+	}
+	got = Sentences("它读取 p。详见 io.Reader。\n或者不？")
+	want = []string{"它读取 p。", "详见 io.Reader。", "或者不？"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Chinese: got %q, want %q", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	m := New()
+	m.AddSegments("Sum appends the current hash to b and returns the resulting slice. It does not change the underlying hash state.",
+		"Sum 将当前哈希追加到 b 中并返回结果切片。它不会改变底层的哈希状态。", "crc32:1", "hash/crc32.Sum")
+	m.AddSegments("Reset resets the Hash to its initial state.", "Reset 将哈希重置为初始状态。", "crc32:2", "")
+	m.AddSegments("Reset resets the Hash to its initial state.", "Reset 将哈希重置为初始状态。", "crc64:2", "")
+	if len(m.Entries) != 4 {
+		t.Fatalf("%d entries, want the comment, its two sentences and Reset", len(m.Entries))
+	}
+
+	got := m.Lookup("Reset resets the Hash to the initial state.", 3, 0.5)
+	if len(got) != 1 || got[0].Chinese != "Reset 将哈希重置为初始状态。" || got[0].Score != 7.0/8 {
+		t.Fatalf("Lookup(Reset) = %+v", got)
+	}
+	if want := []string{"crc32:2", "crc64:2"}; !reflect.DeepEqual(got[0].Sources, want) {
+		t.Errorf("sources %q, want %q", got[0].Sources, want)
+	}
+
+	got = m.Lookup("Sum appends the current hash to b and returns the resulting slice.", 3, 0.5)
+	if len(got) == 0 || got[0].Score != 1 || got[0].Chinese != "Sum 将当前哈希追加到 b 中并返回结果切片。" {
+		t.Errorf("Lookup(Sum) = %+v", got)
+	}
+	if got := m.Lookup("nothing like it", 3, 0.5); len(got) != 0 {
+		t.Errorf("Lookup(nothing) = %+v", got)
+	}
+}
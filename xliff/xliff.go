@@ -0,0 +1,116 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package xliff reads and writes XLIFF 1.2 documents, as used by CAT tools.
+//
+// Only plain-text trans-units are supported: inline markup in source and
+// target elements is not.
+package xliff
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Namespace is the XLIFF 1.2 namespace.
+const Namespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+// Target states used by the translation tools.
+const (
+	StateNew         = "new"
+	StateTranslated  = "translated"
+	StateNeedsReview = "needs-review-translation"
+)
+
+// A Document is an XLIFF document.
+type Document struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string   `xml:"version,attr"`
+	Files   []*File  `xml:"file"`
+}
+
+// A File holds the translation units extracted from one source file.
+type File struct {
+	Original       string  `xml:"original,attr"`
+	SourceLanguage string  `xml:"source-language,attr"`
+	TargetLanguage string  `xml:"target-language,attr,omitempty"`
+	Datatype       string  `xml:"datatype,attr"`
+	Units          []*Unit `xml:"body>trans-unit"`
+}
+
+// A Unit is a trans-unit.
+type Unit struct {
+	ID     string   `xml:"id,attr"`
+	Source Text     `xml:"source"`
+	Target *Target  `xml:"target,omitempty"`
+	Notes  []string `xml:"note"`
+}
+
+// Text is the content of a source element, with white space preserved.
+type Text struct {
+	Space string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+// A Target is the translation of a trans-unit.
+type Target struct {
+	State string `xml:"state,attr,omitempty"`
+	Space string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// MarshalXML encodes t with its line breaks as they are; encoding/xml
+// would escape them as character references, which are hard to read.
+func (t Text) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeText(e, start, "", t.Space, t.Text)
+}
+
+// MarshalXML encodes t with its line breaks as they are.
+func (t Target) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeText(e, start, t.State, t.Space, t.Text)
+}
+
+func encodeText(e *xml.Encoder, start xml.StartElement, state, space, text string) error {
+	if state != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "state"}, Value: state})
+	}
+	if space != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Space: xmlNamespace, Local: "space"}, Value: space})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(xml.CharData(text)); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Read reads a document from r.
+func Read(r io.Reader) (*Document, error) {
+	doc := new(Document)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Write writes doc to w, indented, with an XML declaration.
+func (doc *Document) Write(w io.Writer) error {
+	if doc.Version == "" {
+		doc.Version = "1.2"
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zhconv
+
+// charPairs lists the simplified characters that have a different
+// traditional form, each followed by that form, in Unicode order. It covers
+// the characters of the translations and the common ones of technical
+// writing; a character that is not listed is written unchanged, and
+// reported if it is in simplified.
+//
+// A few simplified characters merge several traditional ones, such as 复
+// for 復 and 複. They map to the form technical text needs most often, and
+// those for which it is often wrong are listed in ambiguous as well.
+const charPairs = "" +
+	"万萬与與丑醜专專业業东東丝絲丢丟两兩严嚴个個丰豐临臨为為举舉么麼义義乐樂习習书書" +
+	"乱亂争爭于於云雲亚亞产產亲親亿億仅僅从從仑崙仓倉们們价價众眾优優会會伞傘伟偉传傳" +
+	"伤傷伦倫伪偽体體余餘侠俠侣侶侥僥侦偵侧側俩倆俭儉债債倾傾偿償储儲儿兒兑兌党黨兰蘭" +
+	"关關兴興兹茲养養兽獸内內册冊写寫军軍农農冯馮冲衝决決况況冻凍净淨准準凉涼减減凑湊" +
+	"几幾凤鳳凭憑凯凱击擊划劃刘劉则則刚剛创創删刪别別刹剎剂劑剑劍剥剝剧劇劝勸办辦务務" +
+	"动動励勵劲勁劳勞势勢勋勳匀勻区區华華协協单單卖賣占佔卢盧卫衛却卻厂廠厅廳历歷压壓" +
+	"厌厭厕廁厢廂厦廈县縣参參双雙发發变變叙敘叠疊叶葉号號叹嘆后後吓嚇吕呂吗嗎听聽启啟" +
+	"吴吳呐吶员員呜嗚响響哑啞哗嘩唤喚啰囉啸嘯喷噴嘱囑团團园園围圍国國图圖圆圓圣聖场場" +
+	"坏壞块塊坚堅坛壇坝壩坞塢垄壟垒壘垦墾垫墊堕墮墙牆壮壯声聲壳殼处處备備复復够夠头頭" +
+	"夸誇夹夾夺奪奋奮奖獎奥奧妆妝妇婦妈媽娄婁娇嬌娱娛婴嬰孙孫学學宁寧宝寶实實审審宪憲" +
+	"宫宮宽寬宾賓寝寢对對寻尋导導寿壽将將尔爾尘塵尝嘗尴尷尽盡层層届屆属屬岁歲岂豈岗崗" +
+	"岛島岭嶺峡峽峦巒币幣帅帥师師帐帳帘簾带帶帧幀帮幫幂冪并並广廣庆慶库庫应應庙廟庞龐" +
+	"开開异異弃棄张張弥彌弯彎弹彈强強归歸当當录錄彦彥彻徹征徵径徑忆憶怀懷态態怂慫总總" +
+	"恋戀恶惡恼惱悦悅悬懸惊驚惩懲惬愜惯慣愿願戏戲战戰户戶扑撲托託执執扩擴扫掃扬揚扰擾" +
+	"抚撫抛拋抢搶护護报報担擔拟擬拢攏拣揀拥擁拦攔拨撥择擇挂掛挡擋挣掙挤擠挥揮损損换換" +
+	"据據掷擲掺摻揽攬搁擱摄攝摆擺摇搖摊攤撑撐敌敵敛斂数數斋齋斗鬥断斷无無旧舊时時旷曠" +
+	"昼晝显顯晓曉暂暫术術朴樸机機杀殺杂雜权權杆桿杠槓条條来來杨楊松鬆极極构構枪槍柜櫃" +
+	"标標栈棧栏欄树樹样樣档檔桥橋梦夢检檢椭橢楼樓横橫欢歡欧歐毁毀毕畢毡氈气氣氢氫汇匯" +
+	"汉漢汤湯沟溝没沒沪滬泄洩泪淚泼潑泽澤洁潔浅淺浊濁测測济濟浏瀏浑渾浓濃涂塗润潤涨漲" +
+	"渐漸温溫游遊湾灣湿濕溃潰滚滾滞滯满滿滤濾滥濫潜潛濒瀕灭滅灯燈灵靈灾災灿燦炉爐点點" +
+	"炼煉烂爛烛燭烟煙烦煩热熱焕煥爱愛爷爺牵牽牺犧状狀犹猶独獨狭狹狮獅狱獄猎獵猫貓献獻" +
+	"玛瑪环環现現琐瑣琼瓊电電画畫畅暢疗療疯瘋痒癢瘾癮皑皚盏盞盐鹽监監盖蓋盘盤着著矿礦" +
+	"码碼砖磚础礎硕碩确確碍礙礼禮祸禍离離种種积積称稱稳穩穷窮窃竊窍竅窝窩竖豎竞競笋筍" +
+	"笔筆笼籠筑築筛篩签簽简簡类類粮糧紧緊纠糾红紅约約级級纪紀纯純纲綱纳納纵縱纷紛纸紙" +
+	"纹紋纺紡纽紐线線练練组組细細织織终終绍紹经經绑綁结結绕繞绘繪给給络絡绝絕统統继繼" +
+	"绩績绪緒续續维維综綜绿綠缀綴缓緩编編缘緣缩縮网網罗羅罚罰罢罷职職联聯聪聰肃肅肤膚" +
+	"肿腫胀脹胁脅胆膽胜勝脉脈脑腦脚腳脸臉腾騰舍捨舰艦艰艱艺藝节節芦蘆苍蒼苏蘇苹蘋范範" +
+	"茧繭荐薦荣榮药藥莱萊获獲营營萧蕭蓝藍蕴蘊虑慮虚虛虫蟲虽雖虾蝦补補衬襯袜襪装裝见見" +
+	"观觀规規视視览覽觉覺触觸计計订訂认認讨討让讓训訓议議讯訊记記讲講许許论論讽諷设設" +
+	"访訪证證评評识識诉訴诊診词詞译譯试試诗詩诚誠话話诞誕诡詭询詢该該详詳语語误誤说說" +
+	"请請诸諸诺諾读讀课課谁誰调調谅諒谈談谋謀谓謂谜謎谢謝谱譜贝貝负負贡貢财財责責贤賢" +
+	"败敗账帳货貨质質贩販贪貪购購贯貫贴貼贵貴贸貿费費资資赋賦赏賞赔賠赖賴赛賽赞讚赠贈" +
+	"赢贏赵趙赶趕趋趨跃躍践踐踪蹤车車轨軌轩軒转轉轭軛轮輪软軟轰轟轴軸轻輕载載较較辄輒" +
+	"辅輔辆輛辈輩辐輻辑輯输輸辞辭辟闢辩辯边邊达達迁遷过過运運还還这這进進远遠违違连連" +
+	"迟遲迹跡适適选選递遞逻邏遗遺邓鄧邮郵邻鄰郑鄭酱醬采採释釋里裡鉴鑑针針钓釣钟鐘钢鋼" +
+	"钥鑰钩鉤钮鈕钱錢钻鑽铁鐵铃鈴铅鉛铜銅铝鋁银銀铺鋪链鏈销銷锁鎖锅鍋错錯锚錨锦錦键鍵" +
+	"镇鎮镜鏡长長门門闪閃闭閉问問闯闖闲閒间間闷悶闹鬧闻聞阀閥阁閣阅閱阐闡阔闊队隊阳陽" +
+	"阴陰阵陣阶階际際陆陸陈陳险險随隨隐隱难難雾霧静靜韦韋韩韓页頁顶頂项項顺順须須顽頑" +
+	"顾顧顿頓颁頒颂頌预預领領颇頗频頻颖穎颗顆题題颜顏额額风風飘飄飞飛饭飯饮飲饰飾饱飽" +
+	"饼餅馆館馈饋马馬驰馳驱驅驶駛驻駐驼駝驾駕骂罵验驗骑騎骗騙骤驟鱼魚鲁魯鲜鮮鸟鳥鸡雞" +
+	"鸣鳴麦麥黄黃齐齊齿齒龄齡龙龍龟龜"
+
+// ambiguous lists the simplified characters whose traditional form often
+// depends on the word: 复 is 復 in 恢复 but 複 in 复制, 系 stays 系 in 系统
+// but is 係 in 关系. Outside of the words of the phrase table, they get
+// their charPairs form, or stay unchanged, and are reported as decisions.
+// Characters such as 后 or 并, whose default is right but in rare words, are
+// not listed: the phrase table handles those words.
+const ambiguous = "丑划历复干征志托斗杆松汇准冲制卷舍尽折注采周游系签范赞辟朴"
+
+// simplified lists the characters of GB 2312 that Big5 lacks, in Unicode
+// order: but for a few rare ones, the simplified forms that Taiwan does not
+// write. Convert reports those it leaves unchanged, for lack of a charPairs
+// form or a phrase, so that they can be added.
+const simplified = "" +
+	"专业丛东丝丢两严丧丨个丬临丶为丽举丿义乌乐乔习乡书买乱争亏亘亚亠产亩亲亵亻亿仅从" +
+	"仑仓仪仫们众会伛伞伟传伤伥伦伧伪伫伲佥侠侣侥侦侧侨侩侪侬俣俦俨俩俪俭倮债倾偬偻偾" +
+	"偿傈傥傧储傩兑兖兰关兴兹养兽冁冂内冈册冖写军农冫冯冲决况冻净凇凉减凑凛凤凫凭凯击" +
+	"凼凿刂刍刘则刚创删别刭刹刽刿剀剂剐剑剥剧劐劝办务劢动励劲劳势勋勐勹匀匦匮区医华协" +
+	"单卖卟卢卤卧卩卫却卺厅历厉压厌厍厕厢厣厦厨厩厮厶县叁参双发变叙叠叶号叹叽吓吕吖吗" +
+	"吡吣启吲吴呋呐呒呓呕呖呗员呙呛呜咏咔咙咛咝咣咤咴哌响哐哑哒哓哔哕哗哙哚哜哝哟唛唠" +
+	"唢唣唤唿啉啧啬啭啮啸喷喹喽喾嗪嗫嗬嗳嗵嘘嘞嘣嘤嘭嘱噍噔噜噻噼嚣嚯团园囱围囵国图圆" +
+	"圹场坂块坚坛坜坝坞坟坠垄垅垆垒垡垦垧垩垫垭垲垴埘埙埚埝埯堑堕塄塬墒墙墚壮声壳壶夂" +
+	"处备够头夹夺奁奂奋奖奥妆妇妈妩妪妫姗姹娄娅娆娇娈娱娲娴婴婵婶媪嫒嫔嫱嬷孙学孪宀宝" +
+	"实宠审宪宫宽宾寝对寻导寿将尔尘尜尝尧尴尽层屉届属屡屦屿岁岂岖岗岘岙岚岛岜岽岿峁峄" +
+	"峡峤峥峦崂崃崭崾嵘嵛嵝嵴巅巛巩巯币帅师帏帐帜带帧帮帱帻帼幂幞幺广庆庐庑库应庙庞废" +
+	"廪廴开弃弑张弥弪弯弹强彐归当录彡彦彻径徕忄忆忧忾态怂怃怄怅怆总怼怿恋恒恳恶恸恹恺" +
+	"恻恼恽悦悫悬悭悯惧惨惩惫惬惭惮惯愠愤愦慑憷懑懒懔戆戋戏戗战戬户扌执扩扪扫扬抚抛抟" +
+	"抠抡抢护报担拟拢拣拥拦拧拨择挚挛挝挞挟挠挡挢挣挤挥捞损捡换捣掳掴掷掸掺掼揞揸揽揿" +
+	"搀搁搂搅携摄摅摆摇摈摊撄撑撵撷撸撺擀擞攒攴攵敌敛敫数斋斓斩断无旧时旷昙昼显晋晓晔" +
+	"晕晖晗暂暧术杀杂权条来杨杩枞枢枣枥枧枨枪枫枭柠柽栀栅标栈栉栊栋栌栎栏树样栾桊桕桠" +
+	"桡桢档桤桥桦桧桨桩梦检棂椁椟椠椤椭楼榀榄榇榈榉榘槛槟槠横樯樱橥橱橹橼檩檫欢欤欧歼" +
+	"殁殇残殒殓殚殡殴毁毂毕毙毡毪毵氇氢氩氲氵氽汇汉汤汹沟没沣沤沥沦沧沩沪沲泪泶泷泸泺" +
+	"泻泼泽泾浃浅浆浇浈浊测浍济浏浑浒浓浔浜涛涝涞涟涠涡涣涤润涧涨涩渊渌渍渎渐渑渔渖渗" +
+	"温湾湿溃溅溆溻滗滚滞滟滠满滢滤滥滦滨滩漤潆潇潋潍潜潴澜濑濒灏灬灭灯灵灾灿炀炉炜炝" +
+	"点炻炼炽烀烁烂烃烛烟烦烧烨烩烫烬热焕焖焘煅煊煳煺熘爱爷牍牦牵牺犊犏犟犭状犷犸犹狈" +
+	"狍狞独狭狮狯狰狱狲猃猎猕猡猪猫猬献猸猹獭玑玛玮环现玺珉珏珐珑珲琏琐琼瑶瑷璎瓒瓯甙" +
+	"电画畅畲畴疃疒疖疗疟疠疡疬疮疯疱疴痃痈痉痖痨痪痫瘅瘗瘘瘪瘫瘾瘿癀癍癔癞癣癫癯皑皱" +
+	"皲盏盐监盖盗盘眍眦着睁睃睐睑瞒瞩矫矶矾矿砀码砖砗砘砚砜砹砺砻砼砾础硇硕硖硗硷碍碛" +
+	"碜碱碹磙礴礻礼祢祯祷祸禀禄禅秃秆积称秽稆税稣稳穑穷窃窍窑窜窝窥窦窭竖竞笃笋笔笕笺" +
+	"笼笾筚筛筝筢筹筻签简箢箦箧箨箩箪箫篑篓篮篼簖籁籴类籼粜粝粤粪粮糁糇糍紧絷纟纠纡红" +
+	"纣纤纥约级纨纩纪纫纬纭纯纰纱纲纳纵纶纷纸纹纺纽纾线绀绁绂练组绅细织终绉绊绋绌绍绎" +
+	"经绐绑绒结绔绕绗绘给绚绛络绝绞统绠绡绢绣绥绦继绨绩绪绫续绮绯绰绱绲绳维绵绶绷绸绺" +
+	"绻综绽绾绿缀缁缂缃缄缅缆缇缈缉缋缌缍缎缏缑缒缓缔缕编缗缘缙缚缛缜缝缟缠缡缢缣缤缥" +
+	"缦缧缨缩缪缫缬缭缮缯缰缱缲缳缴缵罂罗罚罢罱罴羁羟翘耠耢耥耧耱耸耻聂聋职聍联聩聪肀" +
+	"肃肟肠肤肷肼肽肾肿胀胁胆胧胨胩胪胫胬胶脉脍脎脏脐脑脒脓脔脚脱脲脶脸腈腙腚腭腻腼腽" +
+	"腾膑膪臁舆舣舭舰舱舻舾艰艳艹艺节芈芗芜芦芪苁苄苇苈苊苋苌苍苎苏苘苷茎茏茑茔茕茚荆" +
+	"荚荛荜荞荟荠荡荣荤荥荦荧荨荩荪荫荬荭荮药莅莜莱莲莳莴莶获莸莹莺莼萘萜萝萤营萦萧萨" +
+	"葜葱蒇蒈蒉蒋蒌蒽蓝蓟蓠蓣蓥蓦蔷蔸蔹蔺蔼蕲蕴薮藁藓蘖虏虑虚虬虽虾虿蚀蚁蚂蚬蛊蛎蛏蛮" +
+	"蛰蛱蛲蛳蛴蜕蜗蝇蝈蝉蝰蝼蝽蝾螋螨蟮衅衔衤补衬衮袄袅袜袭装裆裢裣裤裥褛褴见观规觅视" +
+	"觇览觉觊觋觌觎觏觐觑觞觯誉誊讠计订讣认讥讦讧讨让讪讫训议讯记讲讳讴讵讶讷许讹论讼" +
+	"讽设访诀证诂诃评诅识诈诉诊诋诌词诎诏译诒诓诔试诖诗诘诙诚诛诜话诞诟诠诡询诣诤该详" +
+	"诧诨诩诫诬语诮误诰诱诲诳说诵诶请诸诹诺读诼诽课诿谀谁谂调谄谅谆谇谈谊谋谌谍谎谏谐" +
+	"谑谒谓谔谕谖谗谘谙谚谛谜谝谟谠谡谢谣谤谥谦谧谨谩谪谫谬谭谮谯谰谱谲谳谴谵谶贝贞负" +
+	"贡财责贤败账货质贩贪贫贬购贮贯贰贱贲贳贴贵贶贷贸费贺贻贼贽贾贿赀赁赂赃资赅赆赇赈" +
+	"赉赊赋赌赍赎赏赐赓赔赕赖赘赙赚赛赜赝赞赠赡赢赣赵趋趱趸跃跄跞践跷跸跹跻踌踪踬踯踺" +
+	"蹑蹒蹰蹿躏躜躯軎车轧轨轩轫转轭轮软轰轱轲轳轴轵轶轷轸轹轺轻轼载轾轿辁辂较辄辅辆辇" +
+	"辈辉辊辋辍辎辏辐辑输辔辕辖辗辘辙辚辞辩辫辶边辽达迁过迈运还这进远违连迟迩迳迹选逊" +
+	"递逦逻遗遥邓邝邬邮邹邺邻郄郏郐郑郓郦郧郸酝酞酰酱酶酽酾酿醌释鉴銮錾鐾钅钆钇针钉钊" +
+	"钋钌钍钎钏钐钒钓钔钕钗钙钚钛钜钝钞钟钠钡钢钣钤钥钦钧钨钩钪钫钬钭钮钯钰钱钲钳钴钵" +
+	"钶钷钸钹钺钻钼钽钾钿铀铁铂铃铄铅铆铈铉铊铋铌铍铎铐铑铒铕铖铗铘铙铛铜铝铞铟铠铡铢" +
+	"铣铤铥铧铨铩铪铫铬铭铮铯铰铱铲铳铴铵银铷铸铹铺铼铽链铿销锁锂锃锄锅锆锇锈锉锊锋锌" +
+	"锍锎锏锐锑锒锓锔锕锖锗锘错锚锛锝锞锟锡锢锣锤锥锦锨锩锪锫锬锭键锯锰锱锲锴锵锶锷锸" +
+	"锹锺锻锼锾锿镀镁镂镄镅镆镇镉镊镌镍镎镏镐镑镒镓镔镖镗镘镙镛镜镝镞镟镡镢镣镤镥镦镧" +
+	"镨镩镪镫镬镭镯镰镱镲镳镶长门闩闪闫闭问闯闰闱闲闳间闵闶闷闸闹闺闻闼闽闾阀阁阂阃阄" +
+	"阅阆阈阉阊阋阌阍阎阏阐阑阒阔阕阖阗阙阚阝队阳阴阵阶际陆陇陈陉陕陧陨险随隐隶隽难雏" +
+	"雠雳雾霁霭靓静靥鞑鞒鞯鞲鞴韦韧韩韪韫韬韵页顶顷顸项顺须顼顽顾顿颀颁颂颃预颅领颇颈" +
+	"颉颊颌颍颏颐频颓颔颖颗题颚颛颜额颞颟颠颡颢颤颥颦颧风飑飒飓飕飘飙飚飞飨餍饣饥饧饨" +
+	"饩饪饫饬饭饮饯饰饱饲饴饵饶饷饺饼饽饿馀馁馄馅馆馇馈馊馋馍馏馐馑馒馓馔馕马驭驮驯驰" +
+	"驱驳驴驵驶驷驸驹驺驻驼驽驾驿骀骁骂骄骅骆骇骈骊骋验骏骐骑骒骓骖骗骘骚骛骜骝骞骟骠" +
+	"骡骢骣骤骥骧骶骺髅髋髌鬏鬓魇魉鱼鱿鲁鲂鲅鲆鲇鲈鲋鲍鲎鲐鲑鲒鲔鲕鲚鲛鲜鲞鲟鲠鲡鲢鲣" +
+	"鲤鲥鲦鲧鲨鲩鲫鲭鲮鲰鲱鲲鲳鲴鲵鲶鲷鲸鲺鲻鲼鲽鳃鳄鳅鳆鳇鳊鳋鳌鳍鳎鳏鳐鳓鳔鳕鳖鳗鳘" +
+	"鳙鳜鳝鳞鳟鳢鸟鸠鸡鸢鸣鸥鸦鸨鸩鸪鸫鸬鸭鸯鸱鸲鸳鸵鸶鸷鸸鸹鸺鸽鸾鸿鹁鹂鹃鹄鹅鹆鹇鹈" +
+	"鹉鹊鹋鹌鹎鹏鹑鹕鹗鹘鹚鹛鹜鹞鹣鹤鹦鹧鹨鹩鹪鹫鹬鹭鹰鹱鹳鹾麦麸麽黄黉黢黩黪黾鼋鼍鼗" +
+	"鼹齄齐齑齿龀龃龄龅龆龇龈龉龊龋龌龙龚龛龟"
@@ -0,0 +1,254 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zhconv
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/zhfmt"
+	"github.com/golang-china/golangdoc.translations/zhtext"
+)
+
+// Convertible reports whether File converts the file, rather than leave it
+// as it is.
+func Convertible(filename string) bool {
+	if zhtext.Kind(filename) != "" {
+		return true
+	}
+	switch filepath.Ext(filename) {
+	case ".article", ".slide", ".xml":
+		return true
+	}
+	return false
+}
+
+// File returns the file src converted to Traditional Chinese, and the
+// decisions taken, at byte offsets of src. Only the Chinese text is
+// converted, as zhtext finds it: the Chinese comments of the stubs and of
+// Go programs, and the translated parts of the HTML documents, with the
+// comments of the programs of their code elements and their notes. The code
+// is left alone: the indented code blocks of the comments, the rest of the
+// code elements of the HTML documents and of codewalk .xml files, and the
+// directives and indented blocks of present files, .article and .slide.
+// Other files are returned unchanged.
+func (c *Converter) File(filename string, src []byte) ([]byte, []Decision, error) {
+	var regions []zhtext.Region
+	html := false
+	switch filepath.Ext(filename) {
+	case ".article", ".slide":
+		regions = presentText(src)
+	case ".xml":
+		regions = []zhtext.Region{{Start: 0, End: len(src)}}
+		html = true
+	default:
+		f, err := zhtext.ParseFile(filename, src)
+		if err != nil {
+			return nil, nil, err
+		}
+		regions = f.Regions
+		html = f.Kind == zhtext.HTML
+		if !html {
+			regions = commentText(src, regions)
+		}
+	}
+
+	var buf bytes.Buffer
+	var decisions []Decision
+	last := 0
+	for _, r := range regions {
+		buf.Write(src[last:r.Start])
+		text := string(src[r.Start:r.End])
+		if html {
+			var ds []Decision
+			text, ds = c.html(text, r.Start)
+			decisions = append(decisions, ds...)
+		} else {
+			var ds []Decision
+			text, ds = c.Convert(text, r.Start)
+			decisions = append(decisions, ds...)
+		}
+		buf.WriteString(text)
+		last = r.End
+	}
+	buf.Write(src[last:])
+	return buf.Bytes(), decisions, nil
+}
+
+// html converts the HTML text, which starts at offset in the file: the text
+// that HTML formats, the comments of the programs of the code elements, such
+// as <pre> blocks, and the whole of the note blocks, such as
+// <pre class="tnote">, which hold the notes of the translators.
+func (c *Converter) html(text string, offset int) (string, []Decision) {
+	var buf bytes.Buffer
+	var decisions []Decision
+	convert := func(start, end int) {
+		out, ds := c.Convert(text[start:end], offset+start)
+		buf.WriteString(out)
+		decisions = append(decisions, ds...)
+	}
+	prose := func(start, end int) {
+		buf.WriteString(zhfmt.HTMLText(text[start:end], func(off int, t string) string {
+			out, ds := c.Convert(t, offset+start+off)
+			decisions = append(decisions, ds...)
+			return out
+		}))
+	}
+	last := 0
+	for _, e := range codeElements(text) {
+		prose(last, e.start)
+		buf.WriteString(text[e.start:e.body])
+		if e.note {
+			convert(e.body, e.end)
+		} else {
+			pos := e.body
+			for _, cm := range codeComments(text[e.body:e.end]) {
+				buf.WriteString(text[pos : e.body+cm.Start])
+				convert(e.body+cm.Start, e.body+cm.End)
+				pos = e.body + cm.End
+			}
+			buf.WriteString(text[pos:e.end])
+		}
+		last = e.end
+	}
+	prose(last, len(text))
+	return buf.String(), decisions
+}
+
+// codeTags are the elements whose content is code.
+var codeTags = map[string]bool{"code": true, "kbd": true, "pre": true, "samp": true, "tt": true}
+
+// A codeElement is an element of codeTags in HTML text.
+type codeElement struct {
+	start int  // start of the start tag
+	body  int  // start of the content, after the start tag
+	end   int  // end of the content, at the end tag
+	note  bool // the element is a note of the translators, not code
+}
+
+var noteRx = regexp.MustCompile(`\bclass="([^"]*\s)?tnote[\s"]`)
+
+// codeElements returns the code elements of the HTML text src, outside of
+// HTML comments and template actions.
+func codeElements(src string) []codeElement {
+	var list []codeElement
+	for i := 0; i < len(src); {
+		var end int
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			end = zhfmt.SkipTo(src, i, "-->")
+		case strings.HasPrefix(src[i:], "{{"):
+			end = zhfmt.SkipTo(src, i, "}}")
+		case src[i] == '<':
+			end = zhfmt.SkipTo(src, i, ">")
+			name := zhfmt.TagName(src[i:end])
+			if !codeTags[name] {
+				break
+			}
+			e := codeElement{start: i, body: end, end: len(src), note: noteRx.MatchString(src[i:end])}
+			if j := strings.Index(src[end:], "</"+name); j >= 0 {
+				e.end = end + j
+			}
+			list = append(list, e)
+			end = e.end
+		default:
+			i++
+			continue
+		}
+		i = end
+	}
+	return list
+}
+
+// codeComments returns the comments of the program or shell session src:
+// from // to the end of the line, but for the // of URLs, from # at the
+// start of a line or after a space to the end of the line, and from /* to
+// */.
+func codeComments(src string) []zhtext.Region {
+	var list []zhtext.Region
+	for i := 0; i < len(src); {
+		var end int
+		switch {
+		case strings.HasPrefix(src[i:], "//") && (i == 0 || src[i-1] != ':'),
+			src[i] == '#' && (i == 0 || src[i-1] == ' ' || src[i-1] == '\t' || src[i-1] == '\n'):
+			end = strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end = zhfmt.SkipTo(src, i+1, "*/")
+		default:
+			i++
+			continue
+		}
+		list = append(list, zhtext.Region{Start: i, End: end})
+		i = end
+	}
+	return list
+}
+
+// presentText returns the lines of a present file that hold text: all but
+// the directives, which start with a period, the comments, which start with
+// #, and the indented code blocks.
+func presentText(src []byte) []zhtext.Region {
+	var regions []zhtext.Region
+	for start := 0; start < len(src); {
+		end := bytes.IndexByte(src[start:], '\n') + 1
+		if end == 0 {
+			end = len(src) - start
+		}
+		end += start
+		line := string(src[start:end])
+		if !strings.HasPrefix(line, ".") && !strings.HasPrefix(line, "#") &&
+			!strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, " ") {
+			regions = append(regions, zhtext.Region{Start: start, End: end})
+		}
+		start = end
+	}
+	return regions
+}
+
+// commentText returns the lines of the comment regions that hold text: all
+// but the indented code blocks, whose lines start with a tab or with two
+// spaces after the comment marker.
+func commentText(src []byte, regions []zhtext.Region) []zhtext.Region {
+	var text []zhtext.Region
+	for _, r := range regions {
+		for start := r.Start; start < r.End; {
+			end := bytes.IndexByte(src[start:r.End], '\n') + 1
+			if end == 0 {
+				end = r.End - start
+			}
+			end += start
+			line := strings.TrimLeft(string(src[start:end]), " \t")
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "/*"), "//")
+			if !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "  ") {
+				text = append(text, zhtext.Region{Start: start, End: end})
+			}
+			start = end
+		}
+	}
+	return text
+}
+
+// TargetName returns the name of the zh_TW file or directory for the zh_CN
+// one: doc_zh_TW.go for doc_zh_CN.go, doc/zh_TW/go_faq.html for
+// doc/zh_CN/go_faq.html, doc/zh_TW for doc/zh_CN.
+func TargetName(name string) string {
+	parts := strings.Split(filepath.ToSlash(name), "/")
+	for i, p := range parts {
+		if p == "zh_CN" {
+			parts[i] = "zh_TW"
+		}
+	}
+	if base := parts[len(parts)-1]; strings.HasPrefix(base, "doc_zh_CN") {
+		parts[len(parts)-1] = "doc_zh_TW" + base[len("doc_zh_CN"):]
+	}
+	return filepath.FromSlash(strings.Join(parts, "/"))
+}
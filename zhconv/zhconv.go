@@ -0,0 +1,185 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zhconv converts the Simplified Chinese (zh_CN) translations to
+// Traditional Chinese as written in Taiwan (zh_TW).
+//
+// The conversion has two layers. A phrase table, a JSON list such as
+//
+//	[
+//		{"cn": "信道", "tw": "通道"},
+//		{"cn": "程序", "tw": "程式"},
+//		{"cn": "之后", "tw": "之後"}
+//	]
+//
+// gives the Taiwan terms for words that differ in more than the shape of
+// their characters, and settles the characters whose traditional form
+// depends on the word. The longest phrase that starts at a position wins.
+// Elsewhere, each character is converted on its own by a character map.
+//
+// A character with several traditional forms, such as 复 (復 or 複), that is
+// not part of a phrase gets its most common form, and is reported as a
+// Decision: adding the word to the phrase table settles it. So is a
+// simplified character that the character map lacks, which is left
+// unchanged: adding it to the map settles it.
+package zhconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Phrase is an entry of the phrase table.
+type Phrase struct {
+	CN string `json:"cn"`
+	TW string `json:"tw"`
+}
+
+// A Decision is an ambiguous character converted by default, or a
+// simplified character left unchanged for lack of a traditional form.
+type Decision struct {
+	Offset   int    // byte offset of the character
+	Char     string // the simplified character
+	Default  string // the form it was given
+	Context  string // the character with its Chinese neighbors
+	Unmapped bool   // the character has no traditional form in the map
+}
+
+// A Converter converts Simplified Chinese text to Traditional Chinese.
+type Converter struct {
+	phrases map[rune][]Phrase // by first rune, longest first
+}
+
+var (
+	chars           = make(map[rune]string) // charPairs
+	ambiguity       = make(map[rune]bool)   // ambiguous
+	simplifiedChars = make(map[rune]bool)   // simplified
+)
+
+func init() {
+	rs := []rune(charPairs)
+	for i := 0; i+1 < len(rs); i += 2 {
+		chars[rs[i]] = string(rs[i+1])
+	}
+	for _, r := range ambiguous {
+		ambiguity[r] = true
+	}
+	for _, r := range simplified {
+		simplifiedChars[r] = true
+	}
+}
+
+// Load reads a phrase table file and returns its converter.
+func Load(filename string) (*Converter, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var phrases []Phrase
+	if err := json.Unmarshal(data, &phrases); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	c, err := New(phrases)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return c, nil
+}
+
+// New returns the converter of the phrase table. Each simplified phrase
+// must be listed once.
+func New(phrases []Phrase) (*Converter, error) {
+	c := &Converter{phrases: make(map[rune][]Phrase)}
+	seen := make(map[string]bool)
+	for _, p := range phrases {
+		if p.CN == "" || p.TW == "" {
+			return nil, fmt.Errorf("empty phrase %q → %q", p.CN, p.TW)
+		}
+		if seen[p.CN] {
+			return nil, fmt.Errorf("phrase %q listed twice", p.CN)
+		}
+		seen[p.CN] = true
+		r, _ := utf8.DecodeRuneInString(p.CN)
+		c.phrases[r] = append(c.phrases[r], p)
+	}
+	for _, list := range c.phrases {
+		sort.Sort(byLength(list))
+	}
+	return c, nil
+}
+
+// byLength sorts phrases longest first, and in Unicode order.
+type byLength []Phrase
+
+func (s byLength) Len() int      { return len(s) }
+func (s byLength) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byLength) Less(i, j int) bool {
+	if len(s[i].CN) != len(s[j].CN) {
+		return len(s[i].CN) > len(s[j].CN)
+	}
+	return s[i].CN < s[j].CN
+}
+
+// Convert returns the text converted to Traditional Chinese, and the
+// decisions taken. The offsets of the decisions are counted from offset.
+// Text other than Chinese is left alone, except where the phrase table says
+// otherwise.
+func (c *Converter) Convert(text string, offset int) (string, []Decision) {
+	var buf bytes.Buffer
+	var decisions []Decision
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if p, ok := c.match(text[i:], r); ok {
+			buf.WriteString(p.TW)
+			i += len(p.CN)
+			continue
+		}
+		tw, ok := chars[r]
+		if !ok {
+			tw = text[i : i+size]
+		}
+		unmapped := !ok && simplifiedChars[r]
+		if ambiguity[r] || unmapped {
+			decisions = append(decisions, Decision{
+				Offset:   offset + i,
+				Char:     text[i : i+size],
+				Default:  tw,
+				Context:  context(text, i, size),
+				Unmapped: unmapped,
+			})
+		}
+		buf.WriteString(tw)
+		i += size
+	}
+	return buf.String(), decisions
+}
+
+// match returns the longest phrase that starts text, whose first rune is r.
+func (c *Converter) match(text string, r rune) (Phrase, bool) {
+	for _, p := range c.phrases[r] {
+		if strings.HasPrefix(text, p.CN) {
+			return p, true
+		}
+	}
+	return Phrase{}, false
+}
+
+// context returns the character text[i:i+size] with the Chinese character
+// on each side of it, if any.
+func context(text string, i, size int) string {
+	start, end := i, i+size
+	if r, n := utf8.DecodeLastRuneInString(text[:start]); unicode.Is(unicode.Han, r) {
+		start -= n
+	}
+	if r, n := utf8.DecodeRuneInString(text[end:]); unicode.Is(unicode.Han, r) {
+		end += n
+	}
+	return text[start:end]
+}
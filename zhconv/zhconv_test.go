@@ -0,0 +1,126 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zhconv
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+var testPhrases = []Phrase{
+	{"信道", "通道"},
+	{"程序", "程式"},
+	{"程序包", "套件"},
+	{"之后", "之後"},
+}
+
+func TestConvert(t *testing.T) {
+	c, err := New(testPhrases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"通过信道发送数据", "通過通道發送數據"},
+		{"该程序包的程序", "該套件的程式"},
+		{"关闭之后 close(ch)", "關閉之後 close(ch)"},
+		{"English text", "English text"},
+	} {
+		if got, ds := c.Convert(tt.in, 0); got != tt.want || len(ds) != 0 {
+			t.Errorf("Convert(%q) = %q, %v, want %q", tt.in, got, ds, tt.want)
+		}
+	}
+
+	got, ds := c.Convert("x复制", 10)
+	if got != "x復制" || len(ds) != 2 {
+		t.Fatalf("Convert(x复制) = %q, %v", got, ds)
+	}
+	if d := ds[0]; d.Offset != 11 || d.Char != "复" || d.Default != "復" || d.Context != "复制" {
+		t.Errorf("decision = %+v", d)
+	}
+}
+
+func TestConvertUnmapped(t *testing.T) {
+	c, err := New(testPhrases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 鲟 is simplified, but not in the character map.
+	got, ds := c.Convert("鲟鱼", 0)
+	if got != "鲟魚" || len(ds) != 1 {
+		t.Fatalf("Convert(鲟鱼) = %q, %v", got, ds)
+	}
+	if d := ds[0]; !d.Unmapped || d.Char != "鲟" || d.Default != "鲟" {
+		t.Errorf("decision = %+v", d)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New([]Phrase{{"程序", "程式"}, {"程序", "程序"}}); err == nil {
+		t.Error("New accepted a phrase listed twice")
+	}
+	if _, err := New([]Phrase{{"程序", ""}}); err == nil {
+		t.Error("New accepted an empty phrase")
+	}
+}
+
+func TestFile(t *testing.T) {
+	c, err := New(testPhrases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name, src, want string
+	}{
+		{
+			"doc_zh_CN.go",
+			"// +build ignore\n\n// Package x 实现了程序。\n//\n//\tx := \"程序\"\npackage x\n\n// F 返回\nfunc F() string { return \"程序\" }\n",
+			"// +build ignore\n\n// Package x 實現了程式。\n//\n//\tx := \"程序\"\npackage x\n\n// F 返回\nfunc F() string { return \"程序\" }\n",
+		},
+		{
+			"go_faq.html",
+			"<div class=\"english\">\n<p>Programs</p>\n</div>\n<p>\n<code>程序</code>是信道的程序。\n</p>\n<pre>\n程序\n</pre>\n",
+			"<div class=\"english\">\n<p>Programs</p>\n</div>\n<p>\n<code>程序</code>是通道的程式。\n</p>\n<pre>\n程序\n</pre>\n",
+		},
+		{
+			"effective_go.html",
+			"<pre>\nx := \"程序\" // 返回后，程序结束\n/* 信道 */ // http://golang.org/程序\n</pre>\n<pre class=\"tnote\">\n单程序的情形\n</pre>\n",
+			"<pre>\nx := \"程序\" // 返回後，程式結束\n/* 通道 */ // http://golang.org/程式\n</pre>\n<pre class=\"tnote\">\n單程式的情形\n</pre>\n",
+		},
+		{
+			"welcome.article",
+			"欢迎\n\n.play hello.go\n\n#appengine: 程序\n\t程序\n",
+			"歡迎\n\n.play hello.go\n\n#appengine: 程序\n\t程序\n",
+		},
+		{"logo.png", "程序", "程序"},
+	} {
+		got, _, err := c.File(tt.name, []byte(tt.src))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"src/fmt/doc_zh_CN.go", "src/fmt/doc_zh_TW.go"},
+		{"src/fmt/doc_zh_CN_windows.go", "src/fmt/doc_zh_TW_windows.go"},
+		{"doc/zh_CN/go_faq.html", "doc/zh_TW/go_faq.html"},
+		{"doc/zh_CN", "doc/zh_TW"},
+		{"zh_CN/content/welcome.article", "zh_TW/content/welcome.article"},
+		{"doc/zh_CNX/a.html", "doc/zh_CNX/a.html"},
+	} {
+		if got := TargetName(filepath.FromSlash(tt.in)); got != filepath.FromSlash(tt.want) {
+			t.Errorf("TargetName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
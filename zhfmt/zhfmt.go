@@ -0,0 +1,464 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zhfmt formats Chinese text the way the translations write it.
+//
+// Text fixes the typography of a run of prose:
+//
+//   - full-width letters and digits become ASCII;
+//   - 「」 and 『』 become “” and ‘’, and so do pairs of straight double
+//     quotes around Chinese text, but not the string literals of code;
+//   - parentheses around Chinese text become full-width, and full-width ones
+//     after an identifier, around no Chinese text, become ASCII;
+//   - the ASCII punctuation that follows a Chinese character becomes
+//     full-width: ，。；：！？;
+//   - a single space separates Chinese characters from ASCII letters and
+//     digits, and no space separates Chinese characters, or full-width
+//     punctuation from anything.
+//
+// Doc formats doc comment text, and also fills its paragraphs to a width
+// that counts the CJK characters as two columns. HTML formats the text of
+// an HTML document, leaving the markup and the code alone.
+//
+// All three are idempotent: formatting formatted text leaves it as it is.
+package zhfmt
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Han reports whether r is a Chinese character.
+func Han(r rune) bool { return unicode.Is(unicode.Han, r) }
+
+// Wide reports whether r is displayed two columns wide: the CJK characters
+// and punctuation, including the CJK quotation marks, which Chinese fonts
+// draw full-width.
+func Wide(r rune) bool {
+	switch {
+	case Han(r), unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r), unicode.Is(unicode.Hangul, r):
+		return true
+	case 0x2e80 <= r && r <= 0x303f, 0xff00 <= r && r <= 0xff60, 0xffe0 <= r && r <= 0xffe6:
+		return true
+	}
+	return strings.ContainsRune("“”‘’…", r)
+}
+
+// fullWidthPunct reports whether r is full-width punctuation, which needs no
+// space on either side.
+func fullWidthPunct(r rune) bool {
+	return 0x3000 <= r && r <= 0x303f || 0xff00 <= r && r <= 0xff60 && !alnum(r)
+}
+
+func alnum(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+}
+
+// wordEnd reports whether r ends in a Latin word: a letter or a digit, or
+// the + and # that end names such as C++ and C#.
+func wordEnd(r []rune) bool {
+	j := len(r)
+	for j > 0 && (r[j-1] == '+' || r[j-1] == '#') {
+		j--
+	}
+	return j > 0 && alnum(r[j-1])
+}
+
+func identRune(r rune) bool { return alnum(r) || r == '_' }
+
+func hasHan(r []rune) bool {
+	for _, c := range r {
+		if Han(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Width returns the number of columns s takes.
+func Width(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if Wide(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// Text returns the run of prose s with its typography fixed.
+func Text(s string) string {
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case 0xff10 <= c && c <= 0xff19, 0xff21 <= c && c <= 0xff3a, 0xff41 <= c && c <= 0xff5a:
+			r[i] = c - 0xff10 + '0'
+		}
+	}
+	quotes(r)
+	parens(r)
+	punct(r)
+	return spacing(r)
+}
+
+// quotes turns corner brackets and the straight double quotes around
+// Chinese text into curly quotes. The straight quotes are paired in order,
+// and left alone if they do not pair up; a pair right after an identifier
+// or a parenthesis quotes a string literal.
+func quotes(r []rune) {
+	var pos []int
+	for i, c := range r {
+		switch c {
+		case '「':
+			r[i] = '“'
+		case '」':
+			r[i] = '”'
+		case '『':
+			r[i] = '‘'
+		case '』':
+			r[i] = '’'
+		case '"':
+			pos = append(pos, i)
+		}
+	}
+	if len(pos)%2 != 0 {
+		return
+	}
+	for k := 0; k < len(pos); k += 2 {
+		a, b := pos[k], pos[k+1]
+		if a > 0 && (identRune(r[a-1]) || r[a-1] == '(') || !hasHan(r[a+1:b]) {
+			continue
+		}
+		r[a], r[b] = '“', '”'
+	}
+}
+
+// parens makes the parentheses around Chinese text full-width, and the
+// full-width ones of a call, around no Chinese text, ASCII. Unmatched
+// parentheses are left alone.
+func parens(r []rune) {
+	var stack []int
+	for i, c := range r {
+		switch c {
+		case '(', '（':
+			stack = append(stack, i)
+		case ')', '）':
+			if len(stack) == 0 {
+				continue
+			}
+			a := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch {
+			case hasHan(r[a+1 : i]):
+				r[a], r[i] = '（', '）'
+			case r[a] == '（' && a > 0 && identRune(r[a-1]):
+				r[a], r[i] = '(', ')'
+			}
+		}
+	}
+}
+
+// punct makes the ASCII punctuation of Chinese sentences full-width: the
+// punctuation that follows a Chinese character or full-width punctuation,
+// or that is followed by a Chinese character, and the periods that end a
+// sentence holding Chinese text. A colon must not start a URL path.
+func punct(r []rune) {
+	for i, c := range r {
+		if !strings.ContainsRune(",;:!?.", c) {
+			continue
+		}
+		var prev, next rune
+		if i > 0 {
+			prev = r[i-1]
+		}
+		if i+1 < len(r) {
+			next = r[i+1]
+		}
+		after := next
+		for j := i + 1; after == ' ' && j < len(r); j++ {
+			after = r[j]
+		}
+		zh := Han(prev) || fullWidthPunct(prev) || prev == '”' || prev == '’'
+		switch c {
+		case ',', ';', '!', '?':
+			if zh || Han(after) {
+				r[i] = fullWidth[c]
+			}
+		case ':':
+			if (zh || Han(after)) && next != '/' {
+				r[i] = fullWidth[c]
+			}
+		case '.':
+			end := next == 0 || next == ' ' || next == '\n' || Han(next)
+			if end && prev != '.' && !abbrev(r[:i]) && (zh || Han(after) || chineseSentence(r[:i])) {
+				r[i] = fullWidth[c]
+			}
+		}
+	}
+}
+
+var fullWidth = map[rune]rune{
+	',': '，', ';': '；', ':': '：', '!': '！', '?': '？', '.': '。',
+}
+
+// abbreviations end in periods that do not end sentences.
+var abbreviations = map[string]bool{
+	"cf": true, "e.g": true, "etc": true, "i.e": true, "vs": true,
+}
+
+// abbrev reports whether r ends in an abbreviation.
+func abbrev(r []rune) bool {
+	j := len(r)
+	for j > 0 && (unicode.IsLetter(r[j-1]) && !Han(r[j-1]) || r[j-1] == '.') {
+		j--
+	}
+	return abbreviations[strings.ToLower(string(r[j:]))]
+}
+
+// chineseSentence reports whether the last sentence of r is Chinese: its
+// Chinese characters take at least as many columns as its ASCII letters.
+func chineseSentence(r []rune) bool {
+	han, latin := 0, 0
+	for j := len(r) - 1; j >= 0; j-- {
+		c := r[j]
+		if strings.ContainsRune("。！？\n", c) || c == '.' && j+1 < len(r) && r[j+1] == ' ' {
+			break
+		}
+		switch {
+		case Han(c):
+			han++
+		case alnum(c):
+			latin++
+		}
+	}
+	return han > 0 && 2*han >= latin
+}
+
+// Spacing returns s with the spaces that Text sets, between Chinese and
+// Latin text and around wide characters, without the other fixes of Text.
+func Spacing(s string) string { return spacing([]rune(s)) }
+
+// spacing puts a single space between Chinese characters and ASCII letters
+// or digits, the trailing + and # of words such as C++ and C# counting as
+// letters, and removes the spaces between wide characters and around
+// full-width punctuation. Other spaces are kept.
+func spacing(r []rune) string {
+	var buf []rune
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		var prev rune
+		if len(buf) > 0 {
+			prev = buf[len(buf)-1]
+		}
+		if c != ' ' {
+			// A minus sign starts a number.
+			minus := c == '-' && i+1 < len(r) && '0' <= r[i+1] && r[i+1] <= '9'
+			if Han(prev) && (alnum(c) || minus) || wordEnd(buf) && Han(c) {
+				buf = append(buf, ' ')
+			}
+			buf = append(buf, c)
+			continue
+		}
+		j := i
+		for j < len(r) && r[j] == ' ' {
+			j++
+		}
+		var next rune
+		if j < len(r) {
+			next = r[j]
+		}
+		switch {
+		case prev == 0 || next == 0 || prev == '\n' || next == '\n':
+			buf = append(buf, r[i:j]...)
+		case fullWidthPunct(prev) || fullWidthPunct(next) || Wide(prev) && Wide(next):
+			// drop
+		case Han(prev) && (alnum(next) || next == '-') || wordEnd(buf) && Han(next):
+			buf = append(buf, ' ')
+		default:
+			buf = append(buf, r[i:j]...)
+		}
+		i = j - 1
+	}
+	return string(buf)
+}
+
+// Kinsoku: the characters that cannot start a line, and those that cannot
+// end one.
+const (
+	noStart = "，。、；：！？）》」』”’…%"
+	noEnd   = "（《「『“‘"
+)
+
+// Wrap fills the paragraph text into lines at most width columns wide.
+// Lines break at spaces, which are dropped, and between two wide
+// characters, but not before closing or after opening punctuation. A word
+// longer than width gets a line of its own. Join undoes Wrap.
+func Wrap(text string, width int) []string {
+	var (
+		lines []string
+		line  string
+		n     int
+	)
+	for _, word := range strings.Fields(text) {
+		for i, atom := range atoms(word) {
+			w := Width(atom)
+			sep := ""
+			if i == 0 && n > 0 {
+				sep = " "
+			}
+			if n > 0 && n+len(sep)+w > width {
+				lines = append(lines, line)
+				line, n, sep = "", 0, ""
+			}
+			line += sep + atom
+			n += len(sep) + w
+		}
+	}
+	if n > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// atoms splits a word at the places a line may break without a space:
+// between two wide characters, unless kinsoku forbids it.
+func atoms(word string) []string {
+	var list []string
+	start := 0
+	prev, _ := utf8.DecodeRuneInString(word)
+	for i, r := range word {
+		if i > 0 && Wide(prev) && Wide(r) && !strings.ContainsRune(noEnd, prev) && !strings.ContainsRune(noStart, r) {
+			list = append(list, word[start:i])
+			start = i
+		}
+		prev = r
+	}
+	return append(list, word[start:])
+}
+
+// Join joins the lines of a paragraph: with no space between two wide
+// characters, and with a single space otherwise.
+func Join(lines []string) string {
+	var s string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case s != "":
+			last, _ := utf8.DecodeLastRuneInString(s)
+			first, _ := utf8.DecodeRuneInString(line)
+			if !Wide(last) || !Wide(first) {
+				s += " "
+			}
+		}
+		s += line
+	}
+	return s
+}
+
+// Doc formats doc comment text, as returned by ast.CommentGroup.Text: the
+// paragraphs are joined, formatted by Text and wrapped at width columns.
+// The indented, preformatted lines and the blank lines are kept as they are.
+func Doc(text string, width int) string {
+	var (
+		out  []string
+		para []string
+	)
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, Wrap(Text(Join(para)), width)...)
+			para = nil
+		}
+	}
+	trailing := strings.HasSuffix(text, "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			out = append(out, line)
+		case line[0] == ' ' || line[0] == '\t':
+			flush()
+			out = append(out, line)
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	s := strings.Join(out, "\n")
+	if trailing {
+		s += "\n"
+	}
+	return s
+}
+
+// skipTags are the elements whose content is code, not prose.
+var skipTags = map[string]bool{
+	"code": true, "kbd": true, "pre": true, "samp": true, "script": true,
+	"style": true, "textarea": true, "tt": true,
+}
+
+// HTML formats the text of an HTML document, or part of one, with Text:
+// the runs of text between the tags, except in the code elements listed in
+// skipTags, in comments and in template actions. The lines are not wrapped.
+func HTML(src string) string {
+	return HTMLText(src, func(_ int, text string) string { return Text(text) })
+}
+
+// HTMLText returns src with each run of text that HTML formats replaced by
+// f of its byte offset in src and its text.
+func HTMLText(src string, f func(offset int, text string) string) string {
+	var buf bytes.Buffer
+	text := 0 // start of the current run of text
+	flush := func(end int) {
+		buf.WriteString(f(text, src[text:end]))
+	}
+	for i := 0; i < len(src); {
+		var end int
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			end = SkipTo(src, i, "-->")
+		case strings.HasPrefix(src[i:], "{{"):
+			end = SkipTo(src, i, "}}")
+		case src[i] == '<':
+			end = SkipTo(src, i, ">")
+			if name := TagName(src[i:end]); skipTags[name] {
+				end = SkipTo(src, end-1, "</"+name)
+				end = SkipTo(src, end-1, ">")
+			}
+		default:
+			i++
+			continue
+		}
+		flush(i)
+		buf.WriteString(src[i:end])
+		i, text = end, end
+	}
+	flush(len(src))
+	return buf.String()
+}
+
+// SkipTo returns the offset after the first sep at or after src[i+1], or
+// len(src). HTMLText skips the tags, comments and template actions of the
+// HTML text with it.
+func SkipTo(src string, i int, sep string) int {
+	if j := strings.Index(src[i+1:], sep); j >= 0 {
+		return i + 1 + j + len(sep)
+	}
+	return len(src)
+}
+
+// TagName returns the lower case name of the start tag, or "" for other
+// tags.
+func TagName(tag string) string {
+	tag = strings.TrimPrefix(tag, "<")
+	n := 0
+	for n < len(tag) && identRune(rune(tag[n])) {
+		n++
+	}
+	return strings.ToLower(tag[:n])
+}
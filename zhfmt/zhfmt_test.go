@@ -0,0 +1,80 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zhfmt
+
+import (
+	"reflect"
+	"testing"
+)
+
+var textTests = []struct{ in, out string }{
+	{"判断字符串s是否包含子串substr.", "判断字符串 s 是否包含子串 substr。"},
+	{"返回-1, 否则返回位置; 见Index", "返回 -1，否则返回位置；见 Index"},
+	{"返回-1，  否则", "返回 -1，否则"},
+	{"按照空白(unicode.IsSpace确定)分割", "按照空白（unicode.IsSpace 确定）分割"},
+	{"调用f（c）", "调用 f(c)"},
+	{"如\"<\"变成\"&lt;\"", "如\"<\"变成\"&lt;\""},
+	{"预定义的\"标准\"Logger和「默认」值", "预定义的“标准”Logger 和“默认”值"},
+	{"调用os.Exit(1). 见http://golang.org/", "调用 os.Exit(1)。见 http://golang.org/"},
+	{"ＵＴＦ８编码", "UTF8 编码"},
+	{"中 文", "中文"},
+	{"配置 vs. 约定", "配置 vs. 约定"},
+	{"Error: '世' has value 0x4e16, too large.", "Error: '世' has value 0x4e16, too large."},
+	{"Go 程序", "Go 程序"},
+	{"用C++或 Java, 或C#写的", "用 C++ 或 Java，或 C# 写的"},
+	{"English text, untouched: f(x).", "English text, untouched: f(x)."},
+}
+
+func TestText(t *testing.T) {
+	for _, tt := range textTests {
+		out := Text(tt.in)
+		if out != tt.out {
+			t.Errorf("Text(%q) = %q, want %q", tt.in, out, tt.out)
+		}
+		if again := Text(out); again != out {
+			t.Errorf("Text(%q) = %q, not idempotent", out, again)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	text := "返回将字符串按照空白（unicode.IsSpace 确定，可以是一到多个连续的空白字符）分割的多个字符串。"
+	want := []string{
+		"返回将字符串按照空白（unicode.IsSpace 确定，",
+		"可以是一到多个连续的空白字符）分割的多个字符",
+		"串。",
+	}
+	lines := Wrap(text, 44)
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Wrap = %q, want %q", lines, want)
+	}
+	for _, line := range lines {
+		if Width(line) > 44 {
+			t.Errorf("line %q is %d columns wide", line, Width(line))
+		}
+	}
+	if got := Join(lines); got != text {
+		t.Errorf("Join(Wrap(text)) = %q", got)
+	}
+}
+
+func TestDoc(t *testing.T) {
+	in := "返回x的平方根，\n如果x<0返回NaN.\n\n\tSqrt(-1) = NaN // 非数, 不变\n"
+	want := "返回 x 的平方根，如果 x<0 返回\nNaN。\n\n\tSqrt(-1) = NaN // 非数, 不变\n"
+	if got := Doc(in, 30); got != want {
+		t.Errorf("Doc = %q, want %q", got, want)
+	}
+	if got := Doc(want, 30); got != want {
+		t.Errorf("Doc not idempotent: %q", got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	in := `<p title="a,b">使用<code>f(x),中</code>调用f.</p><!-- 注释,x -->{{code "中,文"}}<pre>中,文</pre>`
+	want := `<p title="a,b">使用<code>f(x),中</code>调用 f。</p><!-- 注释,x -->{{code "中,文"}}<pre>中,文</pre>`
+	if got := HTML(in); got != want {
+		t.Errorf("HTML = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zhtext finds the Chinese text of the translation files, for the
+// tools that check it: the Chinese blocks of the doc_zh_CN.go stubs, the
+// translated parts of the HTML documents, that is all but the
+// <div class="english"> blocks, and the Chinese comments of Go programs.
+package zhtext
+
+import (
+	"bytes"
+	"go/scanner"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang-china/golangdoc.translations/docstub"
+	"github.com/golang-china/golangdoc.translations/htmldoc"
+)
+
+// Kinds of files.
+const (
+	Stub = "stub" // doc_zh_CN.go stub
+	HTML = "html" // HTML document
+	Go   = "go"   // Go program
+)
+
+// A Region is a range of Chinese text in a file.
+type Region struct {
+	Start, End int // byte offsets
+}
+
+// A File is a file with its Chinese regions, in order.
+type File struct {
+	Name    string
+	Kind    string
+	Src     []byte
+	Regions []Region
+}
+
+// Kind returns the kind of the file, or "" if it is not one the tools check.
+func Kind(filename string) string {
+	switch filepath.Ext(filename) {
+	case ".go":
+		if docstub.IsStubFile(filename) {
+			return Stub
+		}
+		return Go
+	case ".html":
+		return HTML
+	}
+	return ""
+}
+
+// ParseFile finds the Chinese regions of the file. If src is nil, the file
+// is read.
+func ParseFile(filename string, src []byte) (*File, error) {
+	if src == nil {
+		var err error
+		if src, err = ioutil.ReadFile(filename); err != nil {
+			return nil, err
+		}
+	}
+	f := &File{Name: filename, Kind: Kind(filename), Src: src}
+	switch f.Kind {
+	case Stub:
+		fset := token.NewFileSet()
+		stub, err := docstub.ParseFile(fset, filename, src)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range stub.Units {
+			if g := u.ChineseDoc; g != nil {
+				f.Regions = append(f.Regions, Region{fset.Position(g.Pos()).Offset, fset.Position(g.End()).Offset})
+			}
+		}
+	case Go:
+		f.Regions = goComments(src)
+	case HTML:
+		start := 0
+		for _, p := range htmldoc.Parse(filename, src).Pairs {
+			if p.Div.Offset > start {
+				f.Regions = append(f.Regions, Region{start, p.Div.Offset})
+			}
+			start = p.Div.Offset + len(p.Div.Src)
+		}
+		if start < len(src) {
+			f.Regions = append(f.Regions, Region{start, len(src)})
+		}
+	}
+	return f, nil
+}
+
+// goComments returns the comments of a Go program that hold Chinese text.
+// The program need not be valid: it is only scanned.
+func goComments(src []byte) []Region {
+	var regions []Region
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.COMMENT && docstub.HasHan(lit) {
+			start := file.Offset(pos)
+			regions = append(regions, Region{start, start + len(lit)})
+		}
+	}
+	return regions
+}
+
+// Text returns the text of region r.
+func (f *File) Text(r Region) string { return string(f.Src[r.Start:r.End]) }
+
+// Position returns the line and column, both starting at 1, of the byte
+// offset. The column counts bytes, as the go tools do.
+func (f *File) Position(offset int) (line, col int) {
+	line = 1 + bytes.Count(f.Src[:offset], []byte("\n"))
+	return line, offset - (bytes.LastIndexByte(f.Src[:offset], '\n') + 1) + 1
+}
+
+// LineStart returns the offset of the start of the line holding offset i.
+func LineStart(src []byte, i int) int {
+	return bytes.LastIndexByte(src[:i], '\n') + 1
+}
+
+// LineEnd returns the offset after the newline of the line holding offset
+// i, or the end of src.
+func LineEnd(src []byte, i int) int {
+	if j := bytes.IndexByte(src[i:], '\n'); j >= 0 {
+		return i + j + 1
+	}
+	return len(src)
+}
+
+// Walk calls fn for every file the tools check below the roots, which may
+// also name files. Directories whose names start with "." or "_" and
+// testdata directories are skipped. An error returned by fn stops the walk.
+func Walk(roots []string, fn func(f *File, err error) error) error {
+	for _, root := range roots {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				name := info.Name()
+				if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if Kind(path) == "" {
+				return nil
+			}
+			return fn(ParseFile(path, nil))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zhtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func regionTexts(f *File) []string {
+	var list []string
+	for _, r := range f.Regions {
+		list = append(list, f.Text(r))
+	}
+	return list
+}
+
+func TestParseFile(t *testing.T) {
+	for _, tt := range []struct {
+		name, src string
+		want      []string
+	}{
+		{
+			"doc_zh_CN.go",
+			"// +build ignore\n\n// Package p is a test.\n\n// p 包用于测试。\npackage p\n\n// F does it.\n\n// F 完成它。\nfunc F()\n",
+			[]string{"// p 包用于测试。", "// F 完成它。"},
+		},
+		{
+			"prog.go",
+			"package main\n\n// English only.\nfunc main() {\n\tx := 1 // one // 一\n\t/* 二 */\n}\n",
+			[]string{"// one // 一", "/* 二 */"},
+		},
+		{
+			"doc.html",
+			"<h1>标题</h1>\n<div class=\"english\">\n<p>Text.</p>\n</div>\n<p>文本。</p>\n",
+			[]string{"<h1>标题</h1>\n", "\n<p>文本。</p>\n"},
+		},
+	} {
+		f, err := ParseFile(tt.name, []byte(tt.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := regionTexts(f); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	f := &File{Src: []byte("ab\n通道x\n")}
+	for _, tt := range []struct{ offset, line, col int }{
+		{0, 1, 1}, {3, 2, 1}, {9, 2, 7},
+	} {
+		if line, col := f.Position(tt.offset); line != tt.line || col != tt.col {
+			t.Errorf("Position(%d) = %d:%d, want %d:%d", tt.offset, line, col, tt.line, tt.col)
+		}
+	}
+}